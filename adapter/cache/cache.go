@@ -81,6 +81,25 @@ func (s *Store) WriteFile(path string, data []byte) error {
 	return os.WriteFile(path, data, 0o644)
 }
 
+// AppendFile appends data to a file, creating it if necessary. The data is
+// written with a single call in append mode, so records from concurrent
+// processes don't interleave (for writes up to PIPE_BUF bytes on local filesystems).
+func (s *Store) AppendFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // FileMTime returns the modification time of a file.
 func (s *Store) FileMTime(path string) (time.Time, error) {
 	info, err := os.Stat(path)
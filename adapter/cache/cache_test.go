@@ -1,8 +1,11 @@
 package cache
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -132,3 +135,33 @@ func TestReadWriteFile(t *testing.T) {
 		t.Errorf("got %q, want %q", data, "hello")
 	}
 }
+
+func TestAppendFileConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	store := New()
+	path := filepath.Join(dir, "sub", "log.txt")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store.AppendFile(path, []byte(fmt.Sprintf("line %02d\n", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := store.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 50 {
+		t.Fatalf("got %d lines, want 50", len(lines))
+	}
+	for _, l := range lines {
+		if len(l) != len("line 00") {
+			t.Errorf("interleaved line %q", l)
+		}
+	}
+}
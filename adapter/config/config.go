@@ -7,10 +7,16 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/Benniphx/claude-statusline/core/layout"
 	"github.com/Benniphx/claude-statusline/core/types"
 )
 
+// unsetWidth marks MaxWidth as not configured while the config file is parsed.
+const unsetWidth = -1
+
 // Load reads configuration from XDG or legacy paths and returns a Config.
 func Load() types.Config {
 	cfg := types.DefaultConfig()
@@ -20,6 +26,9 @@ func Load() types.Config {
 		cfg.CacheDir = d
 	}
 
+	// MAX_WIDTH=0 turns fitting off, so tell an unset width apart
+	cfg.MaxWidth = unsetWidth
+
 	// Try XDG config, then legacy
 	paths := configPaths()
 	for _, p := range paths {
@@ -28,9 +37,28 @@ func Load() types.Config {
 		}
 	}
 
+	// NO_COLOR (https://no-color.org): any non-empty value disables color
+	if os.Getenv("NO_COLOR") != "" {
+		cfg.NoColor = true
+	}
+
+	// No configured width: fall back to the terminal width, if exported
+	if cfg.MaxWidth == unsetWidth {
+		cfg.MaxWidth = detectWidth()
+	}
+
 	return cfg
 }
 
+// detectWidth returns the terminal width from $COLUMNS, or 0 (unlimited) if unset.
+func detectWidth() int {
+	n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS")))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
+
 func configPaths() []string {
 	var paths []string
 
@@ -100,8 +128,216 @@ func parseFile(path string, cfg *types.Config) bool {
 			if f, err := strconv.ParseFloat(value, 64); err == nil && f > 0 {
 				cfg.CostWeightOpus = f
 			}
+		case "SEGMENTS":
+			if lines := parseLines(value); len(lines) > 0 {
+				cfg.Segments = lines
+			}
+		case "MAX_WIDTH":
+			if n, err := strconv.Atoi(value); err == nil && (n == 0 || (n >= 20 && n <= 1000)) {
+				cfg.MaxWidth = n
+			}
+		case "NO_COLOR":
+			cfg.NoColor = strings.EqualFold(value, "true") || value == "1"
+		case "DIAGNOSTICS":
+			cfg.Diagnostics = strings.EqualFold(value, "true") || value == "1"
+		case "RENDERER":
+			switch v := strings.ToLower(value); v {
+			case "ansi", "powerline", "plain":
+				cfg.Renderer = v
+			}
+		case "THEME":
+			if value != "" {
+				cfg.Theme = strings.ToLower(value)
+			}
+		case "CLOCK":
+			if v := strings.ToLower(value); v == "24h" || v == "12h" {
+				cfg.Locale.Clock = v
+			}
+		case "DECIMAL_SEPARATOR":
+			if value == "." || value == "," {
+				cfg.Locale.Decimal = value
+			}
+		case "THOUSANDS_SEPARATOR":
+			if sep, ok := thousandsSeparators[strings.ToLower(value)]; ok {
+				cfg.Locale.Thousands = sep
+			}
+		case "NUMBER_SUFFIX":
+			if validSuffix(value) {
+				cfg.Locale.Suffix = value
+			}
+		default:
+			if parseBar(key, value, cfg) || parseThreshold(key, value, cfg) {
+				continue
+			}
+			// FORMAT_<SEGMENT>=<template>, e.g. FORMAT_5H={{.Percent}}%
+			if name, ok := strings.CutPrefix(key, "FORMAT_"); ok && name != "" && value != "" {
+				if cfg.Formats == nil {
+					cfg.Formats = map[string]string{}
+				}
+				cfg.Formats[strings.ToLower(name)] = value
+			}
 		}
 	}
 
+	// A thousands separator equal to the decimal separator would make numbers ambiguous
+	if decimal := cfg.Locale.Decimal; cfg.Locale.Thousands == decimal || (decimal == "" && cfg.Locale.Thousands == ".") {
+		cfg.Locale.Thousands = ""
+	}
+
 	return true
 }
+
+// thousandsSeparators maps THOUSANDS_SEPARATOR values to separators.
+var thousandsSeparators = map[string]string{
+	"none":  "",
+	",":     ",",
+	".":     ".",
+	"'":     "'",
+	"_":     "_",
+	"space": " ",
+}
+
+func validSuffix(suffix string) bool {
+	for _, s := range types.Suffixes {
+		if s == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBar applies BAR_<CONTEXT|5H|7D>_<STYLE|WIDTH|FILLED|EMPTY> keys.
+// Invalid values keep the default; it reports whether key is a bar key.
+func parseBar(key, value string, cfg *types.Config) bool {
+	rest, ok := strings.CutPrefix(key, "BAR_")
+	if !ok {
+		return false
+	}
+	name, attr, ok := strings.Cut(rest, "_")
+	if !ok {
+		return false
+	}
+	var bar *types.BarStyle
+	switch name {
+	case "CONTEXT":
+		bar = &cfg.ContextBar
+	case "5H":
+		bar = &cfg.FiveHourBar
+	case "7D":
+		bar = &cfg.SevenDayBar
+	default:
+		return false
+	}
+
+	switch attr {
+	case "STYLE":
+		if v := strings.ToLower(value); validBarStyle(v) {
+			bar.Style = v
+		}
+	case "WIDTH":
+		if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= 40 {
+			bar.Width = n
+		}
+	case "FILLED":
+		if validGlyph(value) {
+			bar.Filled = value
+		}
+	case "EMPTY":
+		if validGlyph(value) {
+			bar.Empty = value
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// parseThreshold applies THRESHOLD_<METRIC>=<warn>,<crit> keys, e.g.
+// THRESHOLD_DAILY=$25,$100. Units ($, %, x) are ignored; warn must not exceed crit.
+// Invalid values keep the default; it reports whether key is a threshold key.
+func parseThreshold(key, value string, cfg *types.Config) bool {
+	metric, ok := strings.CutPrefix(key, "THRESHOLD_")
+	if !ok {
+		return false
+	}
+	var th *types.Threshold
+	switch metric {
+	case "CONTEXT":
+		th = &cfg.Thresholds.Context
+	case "5H":
+		th = &cfg.Thresholds.FiveHour
+	case "7D":
+		th = &cfg.Thresholds.SevenDay
+	case "PACE":
+		th = &cfg.Thresholds.Pace
+	case "SESSION":
+		th = &cfg.Thresholds.Session
+	case "DAILY":
+		th = &cfg.Thresholds.Daily
+	case "HOURLY":
+		th = &cfg.Thresholds.PerHour
+	case "EXTRA":
+		th = &cfg.Thresholds.Extra
+	default:
+		return false
+	}
+
+	warnStr, critStr, found := strings.Cut(value, ",")
+	if !found {
+		return true
+	}
+	warn, err1 := parseLevel(warnStr)
+	crit, err2 := parseLevel(critStr)
+	if err1 == nil && err2 == nil && warn >= 0 && warn <= crit {
+		*th = types.Threshold{Warn: warn, Crit: crit}
+	}
+	return true
+}
+
+// parseLevel parses a threshold level, ignoring a "$" prefix and "%" or "x" suffix.
+func parseLevel(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "$")
+	s = strings.TrimRight(s, "%xX")
+	return strconv.ParseFloat(s, 64)
+}
+
+func validBarStyle(style string) bool {
+	for _, s := range types.BarStyles {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
+// validGlyph reports whether s is a single printable, non-space, single-column
+// character, so bars keep their configured width.
+func validGlyph(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return size > 0 && size == len(s) && r != utf8.RuneError &&
+		unicode.IsGraphic(r) && !unicode.IsSpace(r) && layout.VisibleWidth(s) == 1
+}
+
+// parseLines splits a SEGMENTS value into lines ("|"-separated) of segment names.
+// Lines without any names are dropped.
+func parseLines(value string) [][]string {
+	var lines [][]string
+	for _, part := range strings.Split(value, "|") {
+		if names := parseList(part); len(names) > 0 {
+			lines = append(lines, names)
+		}
+	}
+	return lines
+}
+
+// parseList splits a comma-separated value into trimmed, lower-cased names.
+func parseList(value string) []string {
+	var names []string
+	for _, part := range strings.Split(value, ",") {
+		if name := strings.ToLower(strings.TrimSpace(part)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -84,3 +86,325 @@ WORK_DAYS_PER_WEEK=3
 		t.Errorf("WorkDaysPerWeek = %d, want 3", cfg.WorkDaysPerWeek)
 	}
 }
+
+func TestParseSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	os.WriteFile(path, []byte(`
+SEGMENTS= Context, model ,5h,,7D
+`), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	want := [][]string{{"context", "model", "5h", "7d"}}
+	if !reflect.DeepEqual(cfg.Segments, want) {
+		t.Errorf("Segments = %v, want %v", cfg.Segments, want)
+	}
+}
+
+func TestParseEmptySegmentsKeepsDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	os.WriteFile(path, []byte("SEGMENTS=\n"), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	if cfg.Segments != nil {
+		t.Errorf("Segments = %v, want nil (mode default)", cfg.Segments)
+	}
+}
+
+func TestParseMultiLineSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	os.WriteFile(path, []byte(`
+SEGMENTS=model,context,5h | 7d,session,lines,ollama ||
+MAX_WIDTH=120
+`), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	want := [][]string{{"model", "context", "5h"}, {"7d", "session", "lines", "ollama"}}
+	if !reflect.DeepEqual(cfg.Segments, want) {
+		t.Errorf("Segments = %v, want %v", cfg.Segments, want)
+	}
+	if cfg.MaxWidth != 120 {
+		t.Errorf("MaxWidth = %d, want 120", cfg.MaxWidth)
+	}
+}
+
+func TestParseMaxWidthRange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	os.WriteFile(path, []byte("MAX_WIDTH=10\n"), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	if cfg.MaxWidth != 0 { // 10 is below min 20, keeps default (unlimited)
+		t.Errorf("MaxWidth = %d, want 0", cfg.MaxWidth)
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	os.WriteFile(path, []byte("THEME= Solarized \n"), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	if cfg.Theme != "solarized" {
+		t.Errorf("Theme = %q, want solarized", cfg.Theme)
+	}
+}
+
+func TestParseRenderer(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"powerline", "powerline"},
+		{"Plain", "plain"},
+		{"ansi", "ansi"},
+		{"fancy", "ansi"}, // unknown keeps default
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		os.WriteFile(path, []byte("RENDERER="+tt.value+"\n"), 0o644)
+
+		cfg := types.DefaultConfig()
+		parseFile(path, &cfg)
+		if cfg.Renderer != tt.want {
+			t.Errorf("RENDERER=%s: Renderer = %q, want %q", tt.value, cfg.Renderer, tt.want)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"FORMAT_5H={{.Bar}} {{.Percent}}% {{.Pace}}x",
+		"FORMAT_CONTEXT={{if eq .Percent 0}}-{{end}}",
+		"FORMAT_LINES=",
+		"FORMAT_=ignored",
+	}, "\n")), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+	want := map[string]string{
+		"5h":      "{{.Bar}} {{.Percent}}% {{.Pace}}x",
+		"context": "{{if eq .Percent 0}}-{{end}}",
+	}
+	if !reflect.DeepEqual(cfg.Formats, want) {
+		t.Errorf("Formats = %v, want %v", cfg.Formats, want)
+	}
+}
+
+func TestParseBars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"BAR_5H_STYLE=Dot",
+		"BAR_5H_WIDTH=12",
+		"BAR_7D_STYLE=lower-half-dim",
+		"BAR_7D_FILLED=■",
+		"BAR_7D_EMPTY=□",
+		"BAR_CONTEXT_WIDTH=4",
+		"BAR_CONTEXT_FILLED==",
+		"BAR_CONTEXT_EMPTY=-",
+	}, "\n")), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+	tests := []struct {
+		name string
+		got  types.BarStyle
+		want types.BarStyle
+	}{
+		{"5h", cfg.FiveHourBar, types.BarStyle{Style: "dot", Width: 12, Filled: "█", Empty: "░"}},
+		{"7d", cfg.SevenDayBar, types.BarStyle{Style: "lower-half-dim", Width: 8, Filled: "■", Empty: "□"}},
+		{"context", cfg.ContextBar, types.BarStyle{Style: "thin", Width: 4, Filled: "=", Empty: "-"}},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s bar = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseBarsInvalid(t *testing.T) {
+	tests := []string{
+		"BAR_5H_STYLE=zigzag",
+		"BAR_5H_WIDTH=0",
+		"BAR_5H_WIDTH=41",
+		"BAR_5H_WIDTH=wide",
+		"BAR_5H_FILLED=##",  // more than one character
+		"BAR_5H_FILLED= ",   // space
+		"BAR_5H_EMPTY=🟩",    // double width
+		"BAR_5H_EMPTY=\x01", // control character
+		"BAR_1H_WIDTH=4",    // unknown bar
+	}
+	for _, line := range tests {
+		path := filepath.Join(t.TempDir(), "config")
+		os.WriteFile(path, []byte(line+"\n"), 0o644)
+
+		cfg := types.DefaultConfig()
+		parseFile(path, &cfg)
+		if cfg.FiveHourBar != types.DefaultBar() {
+			t.Errorf("%s: FiveHourBar = %+v, want default", line, cfg.FiveHourBar)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte(strings.Join([]string{
+		"THRESHOLD_CONTEXT=60%,90%",
+		"THRESHOLD_PACE=1.2x, 2x",
+		"THRESHOLD_DAILY=$25,$100",
+		"THRESHOLD_HOURLY=10,20",
+		"THRESHOLD_EXTRA=70%,90%",
+		"THRESHOLD_SESSION=5,1", // warn above crit: ignored
+		"THRESHOLD_5H=-1,80",    // negative: ignored
+		"THRESHOLD_7D=80",       // missing crit: ignored
+		"THRESHOLD_UNKNOWN=1,2",
+	}, "\n")), 0o644)
+
+	cfg := types.DefaultConfig()
+	parseFile(path, &cfg)
+
+	want := types.DefaultThresholds()
+	want.Context = types.Threshold{Warn: 60, Crit: 90}
+	want.Pace = types.Threshold{Warn: 1.2, Crit: 2}
+	want.Daily = types.Threshold{Warn: 25, Crit: 100}
+	want.PerHour = types.Threshold{Warn: 10, Crit: 20}
+	want.Extra = types.Threshold{Warn: 70, Crit: 90}
+	if cfg.Thresholds != want {
+		t.Errorf("Thresholds = %+v, want %+v", cfg.Thresholds, want)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  types.Locale
+	}{
+		{"default", nil, types.Locale{}},
+		{
+			"european",
+			[]string{"CLOCK=24h", "DECIMAL_SEPARATOR=,", "THOUSANDS_SEPARATOR=.", "NUMBER_SUFFIX=k/m"},
+			types.Locale{Clock: "24h", Decimal: ",", Thousands: ".", Suffix: "k/m"},
+		},
+		{
+			"us",
+			[]string{"CLOCK=12H", "THOUSANDS_SEPARATOR=,", "NUMBER_SUFFIX=K/M"},
+			types.Locale{Clock: "12h", Thousands: ",", Suffix: "K/M"},
+		},
+		{"space grouping", []string{"THOUSANDS_SEPARATOR=space"}, types.Locale{Thousands: " "}},
+		{"same separators", []string{"DECIMAL_SEPARATOR=,", "THOUSANDS_SEPARATOR=,"}, types.Locale{Decimal: ","}},
+		{"default decimal clash", []string{"THOUSANDS_SEPARATOR=."}, types.Locale{}},
+		{
+			"invalid",
+			[]string{"CLOCK=13h", "DECIMAL_SEPARATOR=;", "THOUSANDS_SEPARATOR=x", "NUMBER_SUFFIX=T"},
+			types.Locale{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			os.WriteFile(path, []byte(strings.Join(tt.lines, "\n")), 0o644)
+
+			cfg := types.DefaultConfig()
+			parseFile(path, &cfg)
+			if cfg.Locale != tt.want {
+				t.Errorf("Locale = %+v, want %+v", cfg.Locale, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	for value, want := range map[string]bool{"true": true, "1": true, "TRUE": true, "false": false, "yes": false} {
+		path := filepath.Join(t.TempDir(), "config")
+		os.WriteFile(path, []byte("DIAGNOSTICS="+value+"\n"), 0o644)
+
+		cfg := types.DefaultConfig()
+		parseFile(path, &cfg)
+		if cfg.Diagnostics != want {
+			t.Errorf("DIAGNOSTICS=%s: Diagnostics = %v, want %v", value, cfg.Diagnostics, want)
+		}
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	t.Setenv("NO_COLOR", "")
+	if Load().NoColor {
+		t.Error("NoColor should default to false")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !Load().NoColor {
+		t.Error("NO_COLOR env should enable NoColor")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	path := filepath.Join(dir, "claude-statusline", "config")
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	os.WriteFile(path, []byte("NO_COLOR=true\n"), 0o644)
+	if !Load().NoColor {
+		t.Error("NO_COLOR=true in config should enable NoColor")
+	}
+}
+
+func TestLoadMaxWidth(t *testing.T) {
+	tests := []struct {
+		name, config string
+		want         int
+	}{
+		{"unset uses COLUMNS", "", 80},
+		{"explicit width", "MAX_WIDTH=120\n", 120},
+		{"explicit 0 is unlimited", "MAX_WIDTH=0\n", 0},
+		{"out of range uses COLUMNS", "MAX_WIDTH=10\n", 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("COLUMNS", "80")
+			path := filepath.Join(dir, "claude-statusline", "config")
+			os.MkdirAll(filepath.Dir(path), 0o755)
+			os.WriteFile(path, []byte(tt.config), 0o644)
+			if got := Load().MaxWidth; got != tt.want {
+				t.Errorf("MaxWidth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWidth(t *testing.T) {
+	tests := []struct {
+		columns string
+		want    int
+	}{
+		{"", 0},
+		{"120", 120},
+		{" 80 ", 80},
+		{"abc", 0},
+		{"-5", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLUMNS", tt.columns)
+		if got := detectWidth(); got != tt.want {
+			t.Errorf("detectWidth(COLUMNS=%q) = %d, want %d", tt.columns, got, tt.want)
+		}
+	}
+}
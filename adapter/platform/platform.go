@@ -1,9 +1,13 @@
 package platform
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"runtime"
 	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
 )
 
 // Platform provides OS-specific operations and implements multiple port interfaces:
@@ -17,6 +21,47 @@ func Detect() *Platform {
 	return &Platform{goos: runtime.GOOS}
 }
 
+// claudePaths returns where Claude Code keeps its credentials file and the
+// config holding the account logged in with them: both in $CLAUDE_CONFIG_DIR
+// when set, else ~/.claude and ~/.claude.json.
+func claudePaths() (dir, config string, err error) {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir, filepath.Join(dir, ".claude.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(home, ".claude"), filepath.Join(home, ".claude.json"), nil
+}
+
+// withAccount adds the account (oauthAccount in config) that Claude Code
+// logged in with the credentials it stored next to config. A token from the
+// environment may belong to another account and is left without one.
+func withAccount(creds types.Credentials, config string) types.Credentials {
+	creds.AccountID, creds.Email = readAccount(config)
+	return creds
+}
+
+// readAccount reads the account UUID and email of the OAuth login from a
+// Claude Code config file, or "" when missing.
+func readAccount(path string) (id, email string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+	var cfg struct {
+		OAuthAccount struct {
+			AccountUUID  string `json:"accountUuid"`
+			EmailAddress string `json:"emailAddress"`
+		} `json:"oauthAccount"`
+	}
+	if json.Unmarshal(data, &cfg) != nil {
+		return "", ""
+	}
+	return cfg.OAuthAccount.AccountUUID, cfg.OAuthAccount.EmailAddress
+}
+
 // ParseISODate parses an ISO 8601 / RFC 3339 date string.
 func (p *Platform) ParseISODate(s string) (time.Time, error) {
 	return time.Parse(time.RFC3339, s)
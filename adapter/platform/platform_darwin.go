@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -14,7 +15,7 @@ import (
 )
 
 // GetCredentials retrieves OAuth credentials on macOS.
-// Priority: env var → keychain → error.
+// Priority: env var → keychain (with the account of ~/.claude.json) → error.
 func (p *Platform) GetCredentials() (types.Credentials, error) {
 	// 1. Environment variable
 	if token := os.Getenv("CLAUDE_CODE_OAUTH_TOKEN"); token != "" {
@@ -32,7 +33,12 @@ func (p *Platform) GetCredentials() (types.Credentials, error) {
 			} `json:"claudeAiOauth"`
 		}
 		if err := json.Unmarshal([]byte(raw), &creds); err == nil && creds.ClaudeAiOauth.AccessToken != "" {
-			return types.Credentials{OAuthToken: creds.ClaudeAiOauth.AccessToken}, nil
+			// This keychain item is the default login, kept with ~/.claude.json
+			login := types.Credentials{OAuthToken: creds.ClaudeAiOauth.AccessToken}
+			if home, err := os.UserHomeDir(); err == nil {
+				login = withAccount(login, filepath.Join(home, ".claude.json"))
+			}
+			return login, nil
 		}
 	}
 
@@ -15,7 +15,8 @@ import (
 )
 
 // GetCredentials retrieves OAuth credentials on Linux.
-// Priority: env var → credentials file → error.
+// Priority: env var → credentials file in $CLAUDE_CONFIG_DIR or ~/.claude
+// (with the account of the config beside it) → error.
 func (p *Platform) GetCredentials() (types.Credentials, error) {
 	// 1. Environment variable
 	if token := os.Getenv("CLAUDE_CODE_OAUTH_TOKEN"); token != "" {
@@ -23,14 +24,14 @@ func (p *Platform) GetCredentials() (types.Credentials, error) {
 	}
 
 	// 2. Credentials file
-	home, err := os.UserHomeDir()
+	dir, config, err := claudePaths()
 	if err != nil {
 		return types.Credentials{}, fmt.Errorf("no home directory: %w", err)
 	}
 
 	paths := []string{
-		filepath.Join(home, ".claude", ".credentials.json"),
-		filepath.Join(home, ".claude", "credentials.json"),
+		filepath.Join(dir, ".credentials.json"),
+		filepath.Join(dir, "credentials.json"),
 	}
 
 	for _, p := range paths {
@@ -44,7 +45,7 @@ func (p *Platform) GetCredentials() (types.Credentials, error) {
 			} `json:"claudeAiOauth"`
 		}
 		if err := json.Unmarshal(data, &creds); err == nil && creds.ClaudeAiOauth.AccessToken != "" {
-			return types.Credentials{OAuthToken: creds.ClaudeAiOauth.AccessToken}, nil
+			return withAccount(types.Credentials{OAuthToken: creds.ClaudeAiOauth.AccessToken}, config), nil
 		}
 	}
 
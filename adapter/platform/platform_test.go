@@ -1,6 +1,8 @@
 package platform
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -61,3 +63,39 @@ func TestFormatTime(t *testing.T) {
 		t.Errorf("FormatTime = %q, want %q", got, "06.02 14:30")
 	}
 }
+
+func TestReadAccount(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".claude.json")
+	os.WriteFile(path, []byte(`{"numStartups": 12, "oauthAccount": {"accountUuid": "0b1c", "emailAddress": "ben@acme.com", "organizationName": "Acme"}}`), 0o644)
+
+	if id, email := readAccount(path); id != "0b1c" || email != "ben@acme.com" {
+		t.Errorf("readAccount = %q, %q, want 0b1c, ben@acme.com", id, email)
+	}
+	if id, email := readAccount(filepath.Join(dir, "missing.json")); id != "" || email != "" {
+		t.Errorf("readAccount(missing) = %q, %q, want empty", id, email)
+	}
+
+	os.WriteFile(path, []byte(`{"numStartups": 12}`), 0o644) // API key login
+	if id, email := readAccount(path); id != "" || email != "" {
+		t.Errorf("readAccount without oauthAccount = %q, %q, want empty", id, email)
+	}
+}
+
+func TestClaudePaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	dir, config, err := claudePaths()
+	if err != nil || dir != filepath.Join(home, ".claude") || config != filepath.Join(home, ".claude.json") {
+		t.Errorf("claudePaths = %q, %q, %v; want ~/.claude and ~/.claude.json", dir, config, err)
+	}
+
+	// A separate config dir keeps its own login: credentials and account
+	work := filepath.Join(home, "work")
+	t.Setenv("CLAUDE_CONFIG_DIR", work)
+	dir, config, _ = claudePaths()
+	if dir != work || config != filepath.Join(work, ".claude.json") {
+		t.Errorf("claudePaths with CLAUDE_CONFIG_DIR = %q, %q; want both in %s", dir, config, work)
+	}
+}
@@ -1,6 +1,6 @@
 package render
 
-import "fmt"
+import "github.com/Benniphx/claude-statusline/core/types"
 
 // ANSI color codes.
 const (
@@ -19,68 +19,56 @@ const (
 )
 
 // ANSI implements the ports.Renderer interface.
-type ANSI struct{}
+type ANSI struct {
+	theme  Theme
+	depth  ColorDepth
+	stops  []rgb // ok → warn → critical, for gradient bars (nil = flat colors)
+	locale types.Locale
+}
 
-// New creates a new ANSI renderer.
+// New creates a new 16-color ANSI renderer with the default theme.
 func New() *ANSI {
-	return &ANSI{}
+	return NewWithTheme(builtinThemes[DefaultTheme], Color16)
 }
 
-// Colorize applies color based on percentage thresholds: <50 green, <80 yellow, >=80 red.
-func (a *ANSI) Colorize(text string, percent int) string {
-	var color string
-	switch {
-	case percent < 50:
-		color = Green
-	case percent < 80:
-		color = Yellow
-	default:
-		color = Red
+// NewWithTheme creates a new ANSI renderer using the given theme.
+// At Color256 and above, progress bars use a per-cell gradient with eighth-block precision.
+func NewWithTheme(theme Theme, depth ColorDepth) *ANSI {
+	a := &ANSI{theme: theme, depth: depth}
+	if depth >= Color256 {
+		a.stops = gradientStops(theme)
 	}
-	return color + text + Reset
+	return a
 }
 
-// ColorForPercent returns the color code for a percentage threshold.
-func ColorForPercent(percent int) string {
-	switch {
-	case percent < 50:
-		return Green
-	case percent < 80:
-		return Yellow
-	default:
-		return Red
-	}
-}
+// SetLocale sets the number format used by FormatTokens, FormatTokensF and FormatCost.
+func (a *ANSI) SetLocale(l types.Locale) { a.locale = l }
 
-// Color wraps text with the given color code.
-func (a *ANSI) Color(text, color string) string {
-	return color + text + Reset
+// Style wraps text with the theme's color for role.
+// Roles without a color leave the text unchanged.
+func (a *ANSI) Style(text string, role types.Role) string {
+	code := a.theme[role]
+	if code == "" {
+		return text
+	}
+	return code + text + Reset
 }
 
-// Dim applies dim formatting.
+// Dim applies the dim role.
 func (a *ANSI) Dim(text string) string {
-	return DimCode + text + Reset
+	return a.Style(text, types.RoleDim)
 }
 
 // FormatTokens formats a token count with 0 decimals (for max/total context).
 // 200000 → "200K", 32768 → "33K", 500 → "500".
-func (a *ANSI) FormatTokens(n int) string {
-	if n >= 1000 {
-		return fmt.Sprintf("%.0fK", float64(n)/1000.0)
-	}
-	return fmt.Sprintf("%d", n)
-}
+func (a *ANSI) FormatTokens(n int) string { return formatTokens(a.locale, n) }
 
 // FormatTokensF formats a token count with 1 decimal (for used tokens, burn rate).
 // 100000 → "100.0K", 1500 → "1.5K", 500 → "500".
-func (a *ANSI) FormatTokensF(n int) string {
-	if n >= 1000 {
-		return fmt.Sprintf("%.1fK", float64(n)/1000.0)
-	}
-	return fmt.Sprintf("%d", n)
-}
+func (a *ANSI) FormatTokensF(n int) string { return formatTokensF(a.locale, n) }
 
 // FormatCost formats a cost as "$X.XX".
-func (a *ANSI) FormatCost(f float64) string {
-	return fmt.Sprintf("$%.2f", f)
-}
+func (a *ANSI) FormatCost(f float64) string { return formatCost(a.locale, f) }
+
+// Icon returns the emoji for name, or "" if there is none.
+func (a *ANSI) Icon(name types.Icon) string { return types.EmojiIcons[name] }
@@ -0,0 +1,28 @@
+package render
+
+import (
+	"os"
+	"strings"
+)
+
+// ColorDepth is the number of colors a terminal can display.
+type ColorDepth int
+
+const (
+	Color16   ColorDepth = iota // Basic ANSI colors
+	Color256                    // xterm 256-color palette
+	TrueColor                   // 24-bit RGB
+)
+
+// DetectColorDepth reports the terminal's color capability from the environment:
+// COLORTERM=truecolor/24bit → TrueColor, a *256color TERM → Color256, else Color16.
+func DetectColorDepth() ColorDepth {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Color256
+	}
+	return Color16
+}
@@ -1,9 +1,19 @@
 package render
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
 
 // FormatDuration converts milliseconds to a human-readable duration string.
 func (a *ANSI) FormatDuration(ms int) string {
 	min := ms / 60000
 	return fmt.Sprintf("%dm", min)
 }
+
+func formatTokens(l types.Locale, n int) string { return l.Tokens(n, 0) }
+
+func formatTokensF(l types.Locale, n int) string { return l.Tokens(n, 1) }
+
+func formatCost(l types.Locale, f float64) string { return l.Cost(f) }
@@ -0,0 +1,146 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// eighths are left-aligned partial blocks, indexed by filled eighths (1-7).
+var eighths = [8]string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+type rgb struct{ r, g, b int }
+
+// basicRGB approximates the 16 basic ANSI colors (xterm defaults).
+var basicRGB = map[int]rgb{
+	30: {0, 0, 0}, 31: {205, 0, 0}, 32: {0, 205, 0}, 33: {205, 205, 0},
+	34: {0, 0, 238}, 35: {205, 0, 205}, 36: {0, 205, 205}, 37: {229, 229, 229},
+	90: {127, 127, 127}, 91: {255, 0, 0}, 92: {0, 255, 0}, 93: {255, 255, 0},
+	94: {92, 92, 255}, 95: {255, 0, 255}, 96: {0, 255, 255}, 97: {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the xterm 6×6×6 color cube.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// gradientStops returns the ok → warn → critical colors of a theme,
+// or nil if any of them is not a color (e.g. "none").
+func gradientStops(theme Theme) []rgb {
+	var stops []rgb
+	for _, role := range []types.Role{types.RoleOK, types.RoleWarn, types.RoleCritical} {
+		c, ok := rgbOf(theme[role])
+		if !ok {
+			return nil
+		}
+		stops = append(stops, c)
+	}
+	return stops
+}
+
+// gradientAt returns the escape sequence for position pos (0-1) along the
+// ok → warn → critical gradient, quantized to the renderer's color depth.
+// The warn and critical colors are reached at the bar's levels and critical
+// holds beyond. Without a usable gradient it falls back to the flat role color.
+func (a *ANSI) gradientAt(pos float64, bar types.BarStyle) string {
+	levels := barLevels(bar)
+	if a.stops == nil {
+		return a.theme[levels.Role(pos*100)]
+	}
+	warn, crit := levels.Warn/100, levels.Crit/100
+	var c rgb
+	switch {
+	case pos >= crit:
+		c = a.stops[2]
+	case pos >= warn:
+		c = lerp(a.stops[1], a.stops[2], (pos-warn)/(crit-warn))
+	default:
+		c = lerp(a.stops[0], a.stops[1], pos/warn)
+	}
+	if a.depth == TrueColor {
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.r, c.g, c.b)
+	}
+	return fmt.Sprintf("\033[38;5;%dm", to256(c))
+}
+
+// cellColor returns the gradient color for the center of cell i in bar.
+func (a *ANSI) cellColor(i int, bar types.BarStyle) string {
+	return a.gradientAt((float64(i)+0.5)/float64(bar.Width), bar)
+}
+
+func lerp(from, to rgb, t float64) rgb {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	mix := func(x, y int) int { return x + int(math.Round(float64(y-x)*t)) }
+	return rgb{mix(from.r, to.r), mix(from.g, to.g), mix(from.b, to.b)}
+}
+
+// to256 maps a color to the nearest entry of the xterm color cube.
+func to256(c rgb) int {
+	return 16 + 36*nearestLevel(c.r) + 6*nearestLevel(c.g) + nearestLevel(c.b)
+}
+
+func nearestLevel(v int) int {
+	best := 0
+	for i, l := range cubeLevels {
+		if abs(v-l) < abs(v-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// from256 converts an xterm 256-color index to RGB.
+func from256(n int) rgb {
+	switch {
+	case n < 8:
+		return basicRGB[30+n]
+	case n < 16:
+		return basicRGB[90+n-8]
+	case n < 232:
+		n -= 16
+		return rgb{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	default:
+		g := 8 + 10*(n-232)
+		return rgb{g, g, g}
+	}
+}
+
+// rgbOf extracts the foreground color of an escape sequence.
+// When several are present, the last one wins, as in the terminal.
+func rgbOf(seq string) (rgb, bool) {
+	var c rgb
+	found := false
+	for _, part := range strings.Split(seq, "\033[") {
+		params := strings.Split(strings.TrimSuffix(part, "m"), ";")
+		switch {
+		case len(params) == 5 && params[0] == "38" && params[1] == "2":
+			r, _ := strconv.Atoi(params[2])
+			g, _ := strconv.Atoi(params[3])
+			b, _ := strconv.Atoi(params[4])
+			c, found = rgb{r, g, b}, true
+		case len(params) == 3 && params[0] == "38" && params[1] == "5":
+			if n, err := strconv.Atoi(params[2]); err == nil && n >= 0 && n <= 255 {
+				c, found = from256(n), true
+			}
+		case len(params) == 1:
+			n, _ := strconv.Atoi(params[0])
+			if basic, ok := basicRGB[n]; ok {
+				c, found = basic, true
+			}
+		}
+	}
+	return c, found
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,206 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		colorterm, term string
+		want            ColorDepth
+	}{
+		{"truecolor", "xterm-256color", TrueColor},
+		{"24bit", "", TrueColor},
+		{"", "xterm-256color", Color256},
+		{"", "screen-256color", Color256},
+		{"", "xterm", Color16},
+		{"", "", Color16},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLORTERM", tt.colorterm)
+		t.Setenv("TERM", tt.term)
+		if got := DetectColorDepth(); got != tt.want {
+			t.Errorf("DetectColorDepth(COLORTERM=%q, TERM=%q) = %d, want %d", tt.colorterm, tt.term, got, tt.want)
+		}
+	}
+}
+
+// visible strips escape sequences, leaving only the bar glyphs.
+func visible(s string) string {
+	var b strings.Builder
+	inEsc := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEsc = true
+		case inEsc:
+			inEsc = r != 'm'
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func TestGradientBarEighths(t *testing.T) {
+	r := NewWithTheme(builtinThemes["dark"], TrueColor)
+
+	tests := []struct {
+		percent int
+		want    string
+	}{
+		{0, "░░░░░░░░"},
+		{1, "░░░░░░░░"}, // < 1/64
+		{2, "▏░░░░░░░"},
+		{25, "██░░░░░░"},
+		{30, "██▍░░░░░"},
+		{50, "████░░░░"},
+		{99, "███████▉"},
+		{100, "████████"},
+		{150, "████████"},
+	}
+	for _, tt := range tests {
+		if got := visible(r.MakeBar(tt.percent, cells(8))); got != tt.want {
+			t.Errorf("MakeBar(%d, 8) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestGradientBarLevels(t *testing.T) {
+	r := NewWithTheme(builtinThemes["dark"], Color256)
+
+	// Every eighth of an 8-cell bar renders differently: 65 levels (0-64)
+	seen := map[string]bool{}
+	for units := 0; units <= 64; units++ {
+		pct := (units*100 + 63) / 64 // smallest percent reaching this level
+		seen[visible(r.MakeBar(pct, cells(8)))] = true
+	}
+	if len(seen) != 65 {
+		t.Errorf("got %d distinct bars, want 65", len(seen))
+	}
+}
+
+func TestGradientColors(t *testing.T) {
+	r := NewWithTheme(builtinThemes["dark"], TrueColor)
+
+	// dark: green (0,205,0) → yellow (205,205,0) → red (205,0,0)
+	tests := []struct {
+		pos  float64
+		want string
+	}{
+		{0, "\033[38;2;0;205;0m"},
+		{0.5, "\033[38;2;205;205;0m"},
+		{1, "\033[38;2;205;0;0m"},
+		{0.25, "\033[38;2;103;205;0m"},
+	}
+	for _, tt := range tests {
+		if got := r.gradientAt(tt.pos, cells(8)); got != tt.want {
+			t.Errorf("gradientAt(%v) = %q, want %q", tt.pos, got, tt.want)
+		}
+	}
+
+	bar := r.MakeBar(100, cells(8))
+	if !strings.HasPrefix(bar, r.cellColor(0, cells(8))+"█") {
+		t.Errorf("first cell should use the start of the gradient: %q", bar)
+	}
+	if r.cellColor(0, cells(8)) == r.cellColor(7, cells(8)) {
+		t.Error("first and last cells should differ in color")
+	}
+
+	r256 := NewWithTheme(builtinThemes["dark"], Color256)
+	if got := r256.gradientAt(0, cells(8)); got != "\033[38;5;40m" { // cube (0,215,0)
+		t.Errorf("256-color gradientAt(0) = %q, want cube green", got)
+	}
+}
+
+func TestGradientFollowsLevels(t *testing.T) {
+	r := NewWithTheme(builtinThemes["dark"], TrueColor)
+	strict := cells(8)
+	strict.Levels = types.Threshold{Warn: 10, Crit: 15}
+
+	// dark: green (0,205,0) → yellow (205,205,0) → red (205,0,0)
+	tests := []struct {
+		pos  float64
+		bar  types.BarStyle
+		want string
+	}{
+		{0.05, strict, "\033[38;2;103;205;0m"},
+		{0.1, strict, "\033[38;2;205;205;0m"},
+		{0.15, strict, "\033[38;2;205;0;0m"},
+		{0.21, strict, "\033[38;2;205;0;0m"},
+		{0.8, cells(8), "\033[38;2;205;0;0m"},
+		{0.95, cells(8), "\033[38;2;205;0;0m"},
+	}
+	for _, tt := range tests {
+		if got := r.gradientAt(tt.pos, tt.bar); got != tt.want {
+			t.Errorf("gradientAt(%v, %v) = %q, want %q", tt.pos, tt.bar.Levels, got, tt.want)
+		}
+	}
+
+	// At 10/15 levels the cell past 15% of a 21% bar is already critical
+	if bar := r.MakeBar(21, strict); !strings.Contains(bar, "\033[38;2;205;0;0m▋") {
+		t.Errorf("MakeBar(21) with 10/15 levels = %q, want a critical second cell", bar)
+	}
+}
+
+func TestGradientFallsBackWithoutColors(t *testing.T) {
+	theme := Theme{}
+	for role, code := range builtinThemes["dark"] {
+		theme[role] = code
+	}
+	theme["warn"] = ""
+	r := NewWithTheme(theme, TrueColor)
+
+	if got := r.gradientAt(0.1, cells(8)); got != Green {
+		t.Errorf("gradientAt without warn color = %q, want flat ok color", got)
+	}
+
+	// The flat colors follow the bar's levels, like MakeBar without a gradient
+	strict := cells(8)
+	strict.Levels = types.Threshold{Warn: 10, Crit: 20}
+	if got := r.gradientAt(0.3, strict); got != theme[types.RoleCritical] {
+		t.Errorf("gradientAt(0.3) with 10/20 levels = %q, want the critical color", got)
+	}
+	if got := r.gradientAt(0.3, cells(8)); got != Green {
+		t.Errorf("gradientAt(0.3) with default levels = %q, want the ok color", got)
+	}
+}
+
+func TestGradientSplitBar(t *testing.T) {
+	r := NewWithTheme(builtinThemes["dark"], TrueColor)
+
+	// 30% of 8 cells = 2 full cells + 3/8; time covers 4 cells
+	got := r.MakeSplitBar(30, 50, cells(8))
+	if v := visible(got); v != "▇▇▍▁░░░░" {
+		t.Errorf("MakeSplitBar(30, 50, 8) = %q, want %q", v, "▇▇▍▁░░░░")
+	}
+	if !strings.Contains(got, r.cellColor(2, cells(8))+BgRed+"▍") {
+		t.Errorf("partial cell under time should show the time background: %q", got)
+	}
+
+	// 16 colors: whole cells only, unchanged
+	if v := visible(New().MakeSplitBar(30, 50, cells(8))); v != "▇▇▁▁░░░░" {
+		t.Errorf("16-color MakeSplitBar(30, 50, 8) = %q, want %q", v, "▇▇▁▁░░░░")
+	}
+}
+
+func TestColorConversions(t *testing.T) {
+	if got := from256(196); got != (rgb{255, 0, 0}) {
+		t.Errorf("from256(196) = %v, want red", got)
+	}
+	if got := from256(244); got != (rgb{128, 128, 128}) {
+		t.Errorf("from256(244) = %v, want gray", got)
+	}
+	if got := to256(rgb{255, 0, 0}); got != 196 {
+		t.Errorf("to256(red) = %d, want 196", got)
+	}
+	if c, ok := rgbOf("\033[1m\033[38;5;196m"); !ok || c != (rgb{255, 0, 0}) {
+		t.Errorf("rgbOf(bold 196) = %v, %v", c, ok)
+	}
+	if _, ok := rgbOf(DimCode); ok {
+		t.Error("rgbOf(dim) should find no color")
+	}
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Plain implements the ports.Renderer interface without escape codes,
+// for NO_COLOR, logs, screen readers and tools that don't render ANSI.
+// Bars keep their glyphs, so usage and time layers stay distinguishable.
+type Plain struct {
+	locale types.Locale
+}
+
+// NewPlain creates a new plain-text renderer.
+func NewPlain() *Plain {
+	return &Plain{}
+}
+
+// SetLocale sets the number format used by FormatTokens, FormatTokensF and FormatCost.
+func (p *Plain) SetLocale(l types.Locale) { p.locale = l }
+
+// Style returns text unchanged.
+func (p *Plain) Style(text string, role types.Role) string { return text }
+
+// Dim returns text unchanged.
+func (p *Plain) Dim(text string) string { return text }
+
+// MakeBar creates a progress bar from filled and empty glyphs.
+func (p *Plain) MakeBar(percent int, bar types.BarStyle) string {
+	filledGlyph, emptyGlyph := glyphs(bar)
+	filled := clamp(percent, 0, 100) * bar.Width / 100
+	return strings.Repeat(filledGlyph, filled) + strings.Repeat(emptyGlyph, bar.Width-filled)
+}
+
+// MakeSplitBar creates a layered progress bar with the same glyphs as the
+// ANSI styles, e.g. for "thin": ▇ usage over time, █ usage only, ▁ time only, ░ empty.
+func (p *Plain) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
+	filledGlyph, emptyGlyph := glyphs(bar)
+	usageFilled := clamp(usagePct, 0, 100) * bar.Width / 100
+	timeFilled := clamp(timePct, 0, 100) * bar.Width / 100
+	overlapGlyph := filledGlyph
+	if filledGlyph == defaultFilled && (bar.Style == "" || bar.Style == "thin") {
+		overlapGlyph = "▇"
+	}
+
+	var b strings.Builder
+	for i := 0; i < bar.Width; i++ {
+		hasUsage := i < usageFilled
+		hasTime := i < timeFilled
+		switch {
+		case hasUsage && hasTime:
+			b.WriteString(overlapGlyph)
+		case hasUsage:
+			b.WriteString(filledGlyph)
+		case hasTime:
+			b.WriteString(timeGlyph(bar.Style, emptyGlyph))
+		default:
+			b.WriteString(emptyGlyph)
+		}
+	}
+	return b.String()
+}
+
+// FormatTokens formats a token count with 0 decimals: "200K".
+func (p *Plain) FormatTokens(n int) string { return formatTokens(p.locale, n) }
+
+// FormatTokensF formats a token count with 1 decimal: "100.0K".
+func (p *Plain) FormatTokensF(n int) string { return formatTokensF(p.locale, n) }
+
+// FormatCost formats a cost as "$X.XX".
+func (p *Plain) FormatCost(f float64) string { return formatCost(p.locale, f) }
+
+// Icon returns the emoji for name, or "" if there is none.
+func (p *Plain) Icon(name types.Icon) string { return types.EmojiIcons[name] }
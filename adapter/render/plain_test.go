@@ -0,0 +1,63 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestPlainHasNoEscapes(t *testing.T) {
+	p := NewPlain()
+
+	outputs := []string{
+		p.Style("⚠️", types.RoleCritical),
+		p.Dim("t/m"),
+		p.MakeBar(30, cells(8)),
+		p.MakeSplitBar(30, 50, cells(8)),
+	}
+	for _, out := range outputs {
+		if strings.Contains(out, "\033") {
+			t.Errorf("plain output contains escape codes: %q", out)
+		}
+	}
+}
+
+func TestPlainBars(t *testing.T) {
+	p := NewPlain()
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"bar 30%", p.MakeBar(30, cells(10)), "███░░░░░░░"},
+		{"bar clamped", p.MakeBar(150, cells(4)), "████"},
+		{"bar negative", p.MakeBar(-5, cells(4)), "░░░░"},
+		{"split usage ahead", p.MakeSplitBar(60, 30, cells(10)), "▇▇▇███░░░░"},
+		{"split time ahead", p.MakeSplitBar(20, 60, cells(10)), "▇▇▁▁▁▁░░░░"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// Same glyph layout as the ANSI renderer, minus the colors
+	if got, want := p.MakeSplitBar(45, 70, cells(8)), visible(New().MakeSplitBar(45, 70, cells(8))); got != want {
+		t.Errorf("plain split bar = %q, ANSI glyphs = %q", got, want)
+	}
+}
+
+func TestPlainFormatting(t *testing.T) {
+	p := NewPlain()
+	if got := p.FormatTokens(200000); got != "200K" {
+		t.Errorf("FormatTokens = %q, want 200K", got)
+	}
+	if got := p.FormatTokensF(1500); got != "1.5K" {
+		t.Errorf("FormatTokensF = %q, want 1.5K", got)
+	}
+	if got := p.FormatCost(1.234); got != "$1.23" {
+		t.Errorf("FormatCost = %q, want $1.23", got)
+	}
+}
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// powerlineArrow is the solid right-pointing separator (U+E0B0), available in
+// Powerline-patched and Nerd Fonts.
+const powerlineArrow = "\ue0b0"
+
+// NerdFontIcons is the Nerd Font icon set used by the Powerline renderer.
+var NerdFontIcons = map[types.Icon]string{
+	types.IconModel:    "\U000F06A9", // nf-md-robot
+	types.IconContext:  "\U000F035B", // nf-md-memory
+	types.IconClock:    "\U000F0150", // nf-md-clock_outline
+	types.IconFlame:    "\U000F0238", // nf-md-fire
+	types.IconCalendar: "\U000F00ED", // nf-md-calendar
+	types.IconLlama:    "\U000F0ACA", // nf-md-llama
+	types.IconCost:     "\U000F01C1", // nf-md-currency_usd
+	types.IconBranch:   "\U000F062C", // nf-md-source_branch
+}
+
+// Block backgrounds (256-color palette). Segments without their own color
+// alternate between the two neutral shades.
+var (
+	powerlineBackgrounds = map[string]int{
+		"model":  24,  // deep blue
+		"update": 130, // orange
+	}
+	powerlineNeutral = [2]int{236, 238}
+)
+
+// Powerline implements ports.BlockRenderer: each segment is drawn on its own
+// background color with arrow transitions, using Nerd Font icons instead of emoji.
+// Text styling (roles, bars) is delegated to the embedded ANSI renderer.
+type Powerline struct {
+	*ANSI
+}
+
+// NewPowerline creates a Powerline renderer using the given theme.
+func NewPowerline(theme Theme, depth ColorDepth) *Powerline {
+	return &Powerline{ANSI: NewWithTheme(theme, depth)}
+}
+
+// Icon returns the Nerd Font glyph for name.
+func (p *Powerline) Icon(name types.Icon) string { return NerdFontIcons[name] }
+
+// JoinBlocks draws blocks on their backgrounds, separated by arrows whose
+// foreground is the previous block's background.
+func (p *Powerline) JoinBlocks(blocks []ports.Block) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+
+	bgs := make([]int, len(blocks))
+	for i, blk := range blocks {
+		bgs[i] = backgroundFor(blk.Name, i)
+		if i > 0 && bgs[i] == bgs[i-1] {
+			bgs[i] = otherNeutral(bgs[i-1])
+		}
+	}
+
+	var b strings.Builder
+	for i, blk := range blocks {
+		bg := bgSeq(bgs[i])
+		// Styled text ends in a full reset; restore the block background after each
+		text := strings.ReplaceAll(blk.Text, Reset, Reset+bg)
+		b.WriteString(bg + " " + text + " " + Reset + fgSeq(bgs[i]))
+		if i+1 < len(blocks) {
+			b.WriteString(bgSeq(bgs[i+1]) + powerlineArrow)
+		} else {
+			b.WriteString(powerlineArrow + Reset)
+		}
+	}
+	return b.String()
+}
+
+func backgroundFor(name string, i int) int {
+	if bg, ok := powerlineBackgrounds[name]; ok {
+		return bg
+	}
+	return powerlineNeutral[i%2]
+}
+
+func otherNeutral(bg int) int {
+	if bg == powerlineNeutral[0] {
+		return powerlineNeutral[1]
+	}
+	return powerlineNeutral[0]
+}
+
+func fgSeq(n int) string { return fmt.Sprintf("\033[38;5;%dm", n) }
+func bgSeq(n int) string { return fmt.Sprintf("\033[48;5;%dm", n) }
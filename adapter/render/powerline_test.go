@@ -0,0 +1,70 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestPowerlineJoinBlocks(t *testing.T) {
+	p := NewPowerline(builtinThemes["dark"], Color256)
+
+	got := p.JoinBlocks([]ports.Block{
+		{Name: "model", Text: p.Style("Opus", types.RoleOK)},
+		{Name: "context", Text: "Ctx"},
+		{Name: "5h", Text: "5h"},
+	})
+
+	want := bgSeq(24) + " " + Green + "Opus" + Reset + bgSeq(24) + " " + Reset +
+		fgSeq(24) + bgSeq(238) + powerlineArrow +
+		bgSeq(238) + " Ctx " + Reset + fgSeq(238) + bgSeq(236) + powerlineArrow +
+		bgSeq(236) + " 5h " + Reset + fgSeq(236) + powerlineArrow + Reset
+	if got != want {
+		t.Errorf("JoinBlocks =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestPowerlineAdjacentBackgroundsDiffer(t *testing.T) {
+	p := NewPowerline(builtinThemes["dark"], Color16)
+
+	// "update" after a neutral block, then two neutral blocks in a row
+	got := p.JoinBlocks([]ports.Block{
+		{Name: "duration", Text: "a"},
+		{Name: "update", Text: "b"},
+		{Name: "ollama", Text: "c"},
+		{Name: "lines", Text: "d"},
+	})
+	for _, seq := range []string{bgSeq(236) + " a", bgSeq(130) + " b", bgSeq(236) + " c", bgSeq(238) + " d"} {
+		if !strings.Contains(got, seq) {
+			t.Errorf("missing %q in %q", seq, got)
+		}
+	}
+}
+
+func TestPowerlineEmpty(t *testing.T) {
+	if got := NewPowerline(builtinThemes["dark"], Color16).JoinBlocks(nil); got != "" {
+		t.Errorf("JoinBlocks(nil) = %q, want empty", got)
+	}
+}
+
+func TestIconSets(t *testing.T) {
+	p := NewPowerline(builtinThemes["dark"], Color16)
+	for _, icon := range []types.Icon{types.IconModel, types.IconContext, types.IconClock,
+		types.IconFlame, types.IconCalendar, types.IconLlama, types.IconCost} {
+		if p.Icon(icon) == "" {
+			t.Errorf("Nerd Font set has no %q icon", icon)
+		}
+	}
+
+	if got := New().Icon(types.IconFlame); got != "🔥" {
+		t.Errorf("ANSI flame icon = %q, want emoji", got)
+	}
+	if got := New().Icon(types.IconModel); got != "" {
+		t.Errorf("ANSI model icon = %q, want none", got)
+	}
+	if got := NewPlain().Icon(types.IconCalendar); got != "📅" {
+		t.Errorf("plain calendar icon = %q, want emoji", got)
+	}
+}
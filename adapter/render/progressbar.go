@@ -1,124 +1,215 @@
 package render
 
+import (
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
 // MakeBar creates a visual progress bar with ANSI colors.
-// percent is clamped to 0-100, width is number of characters.
-func (a *ANSI) MakeBar(percent, width int) string {
-	if percent < 0 {
-		percent = 0
-	}
-	if percent > 100 {
-		percent = 100
-	}
+// percent is clamped to 0-100; bar sets the width and glyphs.
+func (a *ANSI) MakeBar(percent int, bar types.BarStyle) string {
+	percent = clamp(percent, 0, 100)
+	filledGlyph, emptyGlyph := glyphs(bar)
 
-	filled := percent * width / 100
-	empty := width - filled
-
-	var color string
-	switch {
-	case percent < 50:
-		color = Green
-	case percent < 80:
-		color = Yellow
-	default:
-		color = Red
+	if a.depth >= Color256 {
+		return a.makeGradientBar(percent, bar, filledGlyph, emptyGlyph)
 	}
 
-	bar := color
-	for i := 0; i < filled; i++ {
-		bar += "█"
-	}
-	bar += DimCode
-	for i := 0; i < empty; i++ {
-		bar += "░"
-	}
-	bar += Reset
+	filled := percent * bar.Width / 100
+	empty := bar.Width - filled
+
+	color := a.theme[usageRole(bar, percent)]
+	emptyColor := a.theme[types.RoleBarEmpty]
 
-	return bar
+	return color + strings.Repeat(filledGlyph, filled) + emptyColor + strings.Repeat(emptyGlyph, empty) + Reset
 }
 
-// MakeSplitBar creates a layered progress bar with usage and time indicators.
-// Style parameter selects the visual approach.
-func (a *ANSI) MakeSplitBar(usagePct, timePct, width int) string {
-	return a.MakeSplitBarStyled(usagePct, timePct, width, "thin")
+// makeGradientBar colors each cell by its position along the ok → warn → critical
+// gradient and renders the last partially filled cell as an eighth block,
+// giving width*8 distinguishable levels. Custom filled glyphs have no eighths,
+// so partial cells are left empty for them.
+func (a *ANSI) makeGradientBar(percent int, bar types.BarStyle, filledGlyph, emptyGlyph string) string {
+	width := bar.Width
+	units := percent * width * 8 / 100
+	emptyColor := a.theme[types.RoleBarEmpty]
+
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		switch n := units - i*8; {
+		case n >= 8:
+			b.WriteString(a.cellColor(i, bar) + filledGlyph)
+		case n > 0 && filledGlyph == defaultFilled:
+			b.WriteString(a.cellColor(i, bar) + eighths[n])
+		default:
+			b.WriteString(Reset + emptyColor + emptyGlyph)
+		}
+	}
+	b.WriteString(Reset)
+	return b.String()
 }
 
-// MakeSplitBarStyled renders the split bar in different visual styles (for testing).
-func (a *ANSI) MakeSplitBarStyled(usagePct, timePct, width int, style string) string {
+// MakeSplitBar creates a layered progress bar with usage and time indicators.
+// bar.Style selects how the time layer is drawn (see types.BarStyles).
+func (a *ANSI) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
 	usagePct = clamp(usagePct, 0, 100)
 	timePct = clamp(timePct, 0, 100)
+	width := bar.Width
+	filledGlyph, emptyGlyph := glyphs(bar)
 
-	usageFilled := usagePct * width / 100
+	usageUnits := usagePct * width * 8 / 100 // in eighths of a cell
+	usageFilled := usageUnits / 8
 	timeFilled := timePct * width / 100
 
-	usageColor := ColorForPercent(usagePct)
+	// Sub-cell precision and per-cell gradient need more than 16 colors,
+	// and eighth blocks only line up with the default full block
+	partial := 0
+	if a.depth >= Color256 && filledGlyph == defaultFilled {
+		partial = usageUnits % 8
+	}
+
+	// The thin style's ▇ leaves a gap for the time background; custom glyphs are used as is
+	overlapGlyph := filledGlyph
+	if filledGlyph == defaultFilled {
+		overlapGlyph = "▇"
+	}
+
+	usageColor := a.theme[usageRole(bar, usagePct)]
+	emptyColor := a.theme[types.RoleBarEmpty]
+	timeColor := a.theme[types.RoleBarTime]
+	timeBg := background(timeColor)
+	dimColor := a.theme[types.RoleDim]
+	style := bar.Style
+	if style == "" {
+		style = "thin"
+	}
 
-	var bar string
+	var out string
 	for i := 0; i < width; i++ {
 		hasUsage := i < usageFilled
 		hasTime := i < timeFilled
+		if a.depth >= Color256 {
+			usageColor = a.cellColor(i, bar)
+		}
+
+		// Partially used cell: eighth block, time layer showing behind it
+		if i == usageFilled && partial > 0 {
+			if hasTime && style == "thin" {
+				out += usageColor + timeBg + eighths[partial] + Reset
+			} else {
+				out += usageColor + eighths[partial] + Reset
+			}
+			continue
+		}
 
 		switch style {
 		case "thin":
-			// Style A: ▇ (7/8 block) with red bg where both overlap, ▁ red for time-only
-			// The thin gap at the bottom of ▇ lets the red background show through
+			// Style A: ▇ (7/8 block) with bar-time bg where both overlap, ▁ bar-time for time-only
+			// The thin gap at the bottom of ▇ lets the bar-time background show through
 			switch {
 			case hasUsage && hasTime:
-				bar += usageColor + BgRed + "▇" + Reset
+				out += usageColor + timeBg + overlapGlyph + Reset
 			case hasUsage:
-				bar += usageColor + "█" + Reset
+				out += usageColor + filledGlyph + Reset
 			case hasTime:
-				bar += Red + "▁" + Reset
+				out += timeColor + "▁" + Reset
 			default:
-				bar += DimCode + "░" + Reset
+				out += emptyColor + emptyGlyph + Reset
 			}
 
 		case "bg":
-			// Style B: Normal █ for usage, blue background on ░ for time
+			// Style B: Normal █ for usage, dim ░ on a bar-time background for time
 			switch {
 			case hasUsage:
-				bar += usageColor + "█" + Reset
+				out += usageColor + filledGlyph + Reset
 			case hasTime:
-				bar += BgBlue + DimCode + "░" + Reset
+				out += timeBg + dimColor + emptyGlyph + Reset
 			default:
-				bar += DimCode + "░" + Reset
+				out += emptyColor + emptyGlyph + Reset
 			}
 
 		case "lower-quarter":
-			// Style C: Normal █ for usage, ▂ (lower quarter) in cyan for time
+			// Style C: Normal █ for usage, ▂ (lower quarter) in bar-time for time
 			switch {
 			case hasUsage:
-				bar += usageColor + "█" + Reset
+				out += usageColor + filledGlyph + Reset
 			case hasTime:
-				bar += Cyan + "▂" + Reset
+				out += timeColor + "▂" + Reset
 			default:
-				bar += DimCode + "░" + Reset
+				out += emptyColor + emptyGlyph + Reset
 			}
 
 		case "lower-half-dim":
-			// Style D: Normal █ for usage, dim ▄ for time (subtle)
+			// Style D: Normal █ for usage, dim ▄ in bar-time for time (subtle)
 			switch {
 			case hasUsage:
-				bar += usageColor + "█" + Reset
+				out += usageColor + filledGlyph + Reset
 			case hasTime:
-				bar += DimCode + Blue + "▄" + Reset
+				out += dimColor + timeColor + "▄" + Reset
 			default:
-				bar += DimCode + "░" + Reset
+				out += emptyColor + emptyGlyph + Reset
 			}
 
 		case "dot":
-			// Style E: Normal █ for usage, · dots in blue for time
+			// Style E: Normal █ for usage, · dots in bar-time for time
 			switch {
 			case hasUsage:
-				bar += usageColor + "█" + Reset
+				out += usageColor + filledGlyph + Reset
 			case hasTime:
-				bar += Blue + "·" + Reset
+				out += timeColor + "·" + Reset
 			default:
-				bar += DimCode + "░" + Reset
+				out += emptyColor + emptyGlyph + Reset
 			}
 		}
 	}
 
-	return bar
+	return out
+}
+
+const (
+	defaultFilled = "█"
+	defaultEmpty  = "░"
+)
+
+// glyphs returns the bar's filled and empty glyphs, defaulting to █ and ░.
+func glyphs(bar types.BarStyle) (filled, empty string) {
+	filled, empty = bar.Filled, bar.Empty
+	if filled == "" {
+		filled = defaultFilled
+	}
+	if empty == "" {
+		empty = defaultEmpty
+	}
+	return filled, empty
+}
+
+// usageRole returns the role of used cells by the bar's levels, 50/80 when unset.
+func usageRole(bar types.BarStyle, percent int) types.Role {
+	return barLevels(bar).Role(float64(percent))
+}
+
+// barLevels returns the warn/critical cutoffs of a bar, 50/80 when unset.
+func barLevels(bar types.BarStyle) types.Threshold {
+	if bar.Levels == (types.Threshold{}) {
+		return types.Threshold{Warn: 50, Crit: 80}
+	}
+	return bar.Levels
+}
+
+// timeGlyph returns the glyph of a time-only cell in a split bar style,
+// for renderers that draw the time layer by glyph alone.
+func timeGlyph(style, emptyGlyph string) string {
+	switch style {
+	case "bg":
+		return emptyGlyph
+	case "lower-quarter":
+		return "▂"
+	case "lower-half-dim":
+		return "▄"
+	case "dot":
+		return "·"
+	}
+	return "▁"
 }
 
 func clamp(v, min, max int) int {
@@ -0,0 +1,111 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// TestSplitBarStylesGolden renders every split bar style with each renderer
+// and compares against testdata/splitbar_<style>.golden.
+// Run `go test ./adapter/render -update` after intended changes.
+func TestSplitBarStylesGolden(t *testing.T) {
+	dark := builtinThemes[DefaultTheme]
+	renderers := []struct {
+		name string
+		r    interface {
+			MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string
+		}
+	}{
+		{"ansi16", NewWithTheme(dark, Color16)},
+		{"ansi256", NewWithTheme(dark, Color256)},
+		{"plain", NewPlain()},
+		{"tmux", NewTmux(dark)},
+	}
+	inputs := [][2]int{{0, 0}, {30, 50}, {60, 30}, {45, 70}, {100, 100}}
+
+	for _, style := range types.BarStyles {
+		t.Run(style, func(t *testing.T) {
+			var b strings.Builder
+			for _, bar := range []types.BarStyle{
+				{Style: style, Width: 8, Filled: "█", Empty: "░"},
+				{Style: style, Width: 5, Filled: "■", Empty: "□"},
+			} {
+				for _, rr := range renderers {
+					for _, in := range inputs {
+						got := rr.r.MakeSplitBar(in[0], in[1], bar)
+						fmt.Fprintf(&b, "%s width=%d %s%s usage=%d time=%d: %q\n",
+							rr.name, bar.Width, bar.Filled, bar.Empty, in[0], in[1], got)
+					}
+				}
+			}
+			golden(t, "splitbar_"+style+".golden", b.String())
+		})
+	}
+}
+
+func TestSplitBarTimeLayerFollowsTheme(t *testing.T) {
+	theme := Theme{}
+	for role, seq := range builtinThemes[DefaultTheme] {
+		theme[role] = seq
+	}
+	theme[types.RoleBarTime] = Magenta
+	bar := types.BarStyle{Width: 4, Filled: "█", Empty: "░"}
+
+	for _, style := range types.BarStyles {
+		if style == "thin" {
+			continue
+		}
+		bar.Style = style
+		if got := NewWithTheme(theme, Color16).MakeSplitBar(0, 50, bar); !strings.Contains(got, Magenta) && !strings.Contains(got, background(Magenta)) {
+			t.Errorf("ansi %s: time layer %q does not use the theme's bar-time color", style, got)
+		}
+		if got := NewTmux(theme).MakeSplitBar(0, 50, bar); !strings.Contains(got, "magenta") {
+			t.Errorf("tmux %s: time layer %q does not use the theme's bar-time color", style, got)
+		}
+	}
+}
+
+func TestMakeBarGlyphs(t *testing.T) {
+	bar := types.BarStyle{Width: 5, Filled: "=", Empty: "-"}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"plain", NewPlain().MakeBar(60, bar), "===--"},
+		{"ansi16", visible(New().MakeBar(60, bar)), "===--"},
+		// Custom glyphs have no eighth blocks, so the partial cell stays empty
+		{"ansi256", visible(NewWithTheme(builtinThemes[DefaultTheme], Color256).MakeBar(70, bar)), "===--"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: MakeBar = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (run with -update if intended)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
@@ -3,53 +3,16 @@ package render
 import (
 	"strings"
 	"testing"
-)
-
-func TestColorize(t *testing.T) {
-	r := New()
 
-	tests := []struct {
-		text    string
-		percent int
-		color   string
-	}{
-		{"ok", 0, Green},
-		{"ok", 49, Green},
-		{"warn", 50, Yellow},
-		{"warn", 79, Yellow},
-		{"crit", 80, Red},
-		{"crit", 100, Red},
-	}
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
 
-	for _, tt := range tests {
-		got := r.Colorize(tt.text, tt.percent)
-		if !strings.HasPrefix(got, tt.color) {
-			t.Errorf("Colorize(%q, %d) should start with color %q, got %q", tt.text, tt.percent, tt.color, got)
-		}
-		if !strings.HasSuffix(got, Reset) {
-			t.Errorf("Colorize(%q, %d) should end with Reset", tt.text, tt.percent)
-		}
-	}
-}
-
-func TestColorForPercent(t *testing.T) {
-	tests := []struct {
-		percent int
-		want    string
-	}{
-		{0, Green},
-		{49, Green},
-		{50, Yellow},
-		{79, Yellow},
-		{80, Red},
-		{100, Red},
-	}
-	for _, tt := range tests {
-		got := ColorForPercent(tt.percent)
-		if got != tt.want {
-			t.Errorf("ColorForPercent(%d) = %q, want %q", tt.percent, got, tt.want)
-		}
-	}
+// cells returns the default bar with the given width.
+func cells(width int) types.BarStyle {
+	bar := types.DefaultBar()
+	bar.Width = width
+	return bar
 }
 
 func TestMakeBar(t *testing.T) {
@@ -71,7 +34,7 @@ func TestMakeBar(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := r.MakeBar(tt.percent, tt.width)
+		got := r.MakeBar(tt.percent, cells(tt.width))
 		filledCount := strings.Count(got, "█")
 		emptyCount := strings.Count(got, "░")
 		if filledCount != tt.filled {
@@ -110,7 +73,7 @@ func TestMakeSplitBar(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := r.MakeSplitBar(tt.usage, tt.time, tt.width)
+			got := r.MakeSplitBar(tt.usage, tt.time, cells(tt.width))
 			both := strings.Count(got, "▇")
 			usage := strings.Count(got, "█")
 			timeLine := strings.Count(got, "▁")
@@ -205,6 +168,32 @@ func TestFormatCost(t *testing.T) {
 	}
 }
 
+func TestFormatLocale(t *testing.T) {
+	locale := types.Locale{Decimal: ",", Thousands: ".", Suffix: "K/M"}
+	renderers := map[string]interface {
+		ports.Renderer
+		SetLocale(types.Locale)
+	}{
+		"ansi":      New(),
+		"powerline": NewPowerline(builtinThemes[DefaultTheme], Color16),
+		"plain":     NewPlain(),
+		"tmux":      NewTmux(builtinThemes[DefaultTheme]),
+	}
+
+	for name, r := range renderers {
+		r.SetLocale(locale)
+		if got := r.FormatTokens(1500000); got != "2M" {
+			t.Errorf("%s: FormatTokens = %q, want 2M", name, got)
+		}
+		if got := r.FormatTokensF(90000); got != "90,0K" {
+			t.Errorf("%s: FormatTokensF = %q, want 90,0K", name, got)
+		}
+		if got := r.FormatCost(1234.5); got != "$1.234,50" {
+			t.Errorf("%s: FormatCost = %q, want $1.234,50", name, got)
+		}
+	}
+}
+
 func TestFormatDuration(t *testing.T) {
 	r := New()
 
@@ -234,25 +223,27 @@ func TestDim(t *testing.T) {
 	}
 }
 
-func TestColor(t *testing.T) {
+func TestStyle(t *testing.T) {
 	r := New()
 
 	tests := []struct {
-		text  string
-		color string
-		want  string
+		text string
+		role types.Role
+		want string
 	}{
-		{"ok", Green, Green + "ok" + Reset},
-		{"warn", Yellow, Yellow + "warn" + Reset},
-		{"crit", Red, Red + "crit" + Reset},
-		{"info", Cyan, Cyan + "info" + Reset},
-		{"tpm", Magenta, Magenta + "tpm" + Reset},
+		{"ok", types.RoleOK, Green + "ok" + Reset},
+		{"warn", types.RoleWarn, Yellow + "warn" + Reset},
+		{"crit", types.RoleCritical, Red + "crit" + Reset},
+		{"info", types.RoleResetTime, Cyan + "info" + Reset},
+		{"tpm", types.RoleBurn, Magenta + "tpm" + Reset},
+		{"new", types.RoleAccent, Yellow + "new" + Reset},
+		{"x", types.Role("unknown"), "x"},
 	}
 
 	for _, tt := range tests {
-		got := r.Color(tt.text, tt.color)
+		got := r.Style(tt.text, tt.role)
 		if got != tt.want {
-			t.Errorf("Color(%q, %q) = %q, want %q", tt.text, tt.color, got, tt.want)
+			t.Errorf("Style(%q, %q) = %q, want %q", tt.text, tt.role, got, tt.want)
 		}
 	}
 }
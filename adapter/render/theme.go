@@ -0,0 +1,271 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Theme maps semantic color roles to ANSI escape sequences.
+// Roles missing from a theme render uncolored.
+type Theme map[types.Role]string
+
+// DefaultTheme is the theme used when none is configured.
+const DefaultTheme = "dark"
+
+// Built-in themes. dark reproduces the original hard-coded colors.
+var builtinThemes = map[string]Theme{
+	"dark": {
+		types.RoleOK:        Green,
+		types.RoleWarn:      Yellow,
+		types.RoleCritical:  Red,
+		types.RoleDim:       DimCode,
+		types.RoleAccent:    Yellow,
+		types.RoleBurn:      Magenta,
+		types.RoleResetTime: Cyan,
+		types.RoleBarEmpty:  DimCode,
+		types.RoleBarTime:   Red,
+	},
+	// light avoids yellow and dim, which wash out on white backgrounds.
+	"light": {
+		types.RoleOK:        "\033[38;5;28m",
+		types.RoleWarn:      "\033[38;5;130m",
+		types.RoleCritical:  "\033[38;5;160m",
+		types.RoleDim:       "\033[38;5;244m",
+		types.RoleAccent:    "\033[38;5;25m",
+		types.RoleBurn:      "\033[38;5;90m",
+		types.RoleResetTime: "\033[38;5;31m",
+		types.RoleBarEmpty:  "\033[38;5;250m",
+		types.RoleBarTime:   "\033[38;5;167m",
+	},
+	// solarized uses the canonical Solarized accent palette.
+	"solarized": {
+		types.RoleOK:        "\033[38;2;133;153;0m",
+		types.RoleWarn:      "\033[38;2;181;137;0m",
+		types.RoleCritical:  "\033[38;2;220;50;47m",
+		types.RoleDim:       "\033[38;2;88;110;117m",
+		types.RoleAccent:    "\033[38;2;38;139;210m",
+		types.RoleBurn:      "\033[38;2;211;54;130m",
+		types.RoleResetTime: "\033[38;2;42;161;152m",
+		types.RoleBarEmpty:  "\033[38;2;88;110;117m",
+		types.RoleBarTime:   "\033[38;2;203;75;22m",
+	},
+	// high-contrast uses bold bright colors and no dim.
+	"high-contrast": {
+		types.RoleOK:        "\033[1m\033[92m",
+		types.RoleWarn:      "\033[1m\033[93m",
+		types.RoleCritical:  "\033[1m\033[91m",
+		types.RoleDim:       "\033[97m",
+		types.RoleAccent:    "\033[1m\033[96m",
+		types.RoleBurn:      "\033[1m\033[95m",
+		types.RoleResetTime: "\033[1m\033[96m",
+		types.RoleBarEmpty:  "\033[37m",
+		types.RoleBarTime:   "\033[91m",
+	},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	return []string{"dark", "light", "solarized", "high-contrast"}
+}
+
+// LoadTheme resolves a theme by name: a built-in theme, or a user theme file
+// in $XDG_CONFIG_HOME/claude-statusline/themes/<name>.
+// Unknown or unreadable themes fall back to the default theme.
+func LoadTheme(name string) (Theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultTheme
+	}
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return builtinThemes[DefaultTheme], fmt.Errorf("invalid theme name %q", name)
+	}
+	dir := themesDir()
+	if dir == "" {
+		return builtinThemes[DefaultTheme], fmt.Errorf("theme %q not found", name)
+	}
+	t, err := parseThemeFile(filepath.Join(dir, name))
+	if err != nil {
+		return builtinThemes[DefaultTheme], err
+	}
+	return t, nil
+}
+
+func themesDir() string {
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		xdg = filepath.Join(home, ".config")
+	}
+	return filepath.Join(xdg, "claude-statusline", "themes")
+}
+
+// parseThemeFile reads a user theme. The format matches the config file:
+//
+//	# Optional base theme; roles not listed below are inherited from it
+//	base=light
+//	ok=green
+//	warn=#d78700
+//	critical=bold red
+//	bar-empty=244
+//
+// Unknown roles and invalid color specs are ignored.
+func parseThemeFile(path string) (Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	type entry struct {
+		role types.Role
+		code string
+	}
+	base := DefaultTheme
+	var entries []entry
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "base" {
+			if _, ok := builtinThemes[strings.ToLower(value)]; ok {
+				base = strings.ToLower(value)
+			}
+			continue
+		}
+		if !isRole(types.Role(key)) {
+			continue
+		}
+		if code, ok := ParseColorSpec(value); ok {
+			entries = append(entries, entry{types.Role(key), code})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	t := Theme{}
+	for role, code := range builtinThemes[base] {
+		t[role] = code
+	}
+	for _, e := range entries {
+		t[e.role] = e.code
+	}
+	return t, nil
+}
+
+func isRole(role types.Role) bool {
+	for _, r := range types.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+var colorNames = map[string]int{
+	"black": 30, "red": 31, "green": 32, "yellow": 33,
+	"blue": 34, "magenta": 35, "cyan": 36, "white": 37,
+	"bright-black": 90, "bright-red": 91, "bright-green": 92, "bright-yellow": 93,
+	"bright-blue": 94, "bright-magenta": 95, "bright-cyan": 96, "bright-white": 97,
+	"gray": 90, "grey": 90,
+}
+
+var attrNames = map[string]int{
+	"bold": 1, "dim": 2, "italic": 3, "underline": 4,
+}
+
+// ParseColorSpec converts a color spec into an ANSI escape sequence.
+// A spec is one or more space- or "+"-separated parts, each one of:
+// an attribute (bold, dim, italic, underline), a color name (red, bright-red, …),
+// a 256-color index (0-255), or a truecolor hex value (#rrggbb).
+// "none" yields an empty sequence (uncolored).
+func ParseColorSpec(spec string) (string, bool) {
+	parts := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ' ' || r == '+'
+	})
+	if len(parts) == 0 {
+		return "", false
+	}
+	if len(parts) == 1 && parts[0] == "none" {
+		return "", true
+	}
+
+	var b strings.Builder
+	for _, p := range parts {
+		switch {
+		case attrNames[p] != 0:
+			fmt.Fprintf(&b, "\033[%dm", attrNames[p])
+		case colorNames[p] != 0:
+			fmt.Fprintf(&b, "\033[%dm", colorNames[p])
+		case strings.HasPrefix(p, "#") && len(p) == 7:
+			rgb, err := strconv.ParseUint(p[1:], 16, 32)
+			if err != nil {
+				return "", false
+			}
+			fmt.Fprintf(&b, "\033[38;2;%d;%d;%dm", rgb>>16, (rgb>>8)&0xff, rgb&0xff)
+		default:
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 || n > 255 {
+				return "", false
+			}
+			fmt.Fprintf(&b, "\033[38;5;%dm", n)
+		}
+	}
+	return b.String(), true
+}
+
+// background converts the foreground colors in an escape sequence to the
+// matching background colors. Attributes are dropped.
+func background(seq string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(seq, "\033[") {
+		params := strings.TrimSuffix(part, "m")
+		if params == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(params, "38;"):
+			b.WriteString("\033[48;" + params[3:] + "m")
+		default:
+			n, err := strconv.Atoi(params)
+			if err != nil {
+				continue
+			}
+			if (n >= 30 && n <= 37) || (n >= 90 && n <= 97) {
+				fmt.Fprintf(&b, "\033[%dm", n+10)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Hex returns the role's foreground color as "#rrggbb", or "" if it has none.
+func (t Theme) Hex(role types.Role) string {
+	c, ok := rgbOf(t[role])
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+}
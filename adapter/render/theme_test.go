@@ -0,0 +1,145 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestBuiltinThemesCoverAllRoles(t *testing.T) {
+	for _, name := range ThemeNames() {
+		theme, err := LoadTheme(name)
+		if err != nil {
+			t.Fatalf("LoadTheme(%q): %v", name, err)
+		}
+		for _, role := range types.Roles {
+			if theme[role] == "" {
+				t.Errorf("theme %q has no color for role %q", name, role)
+			}
+		}
+	}
+}
+
+func TestParseColorSpec(t *testing.T) {
+	tests := []struct {
+		spec   string
+		want   string
+		wantOK bool
+	}{
+		{"red", Red, true},
+		{"Bright-Red", "\033[91m", true},
+		{"dim+blue", DimCode + Blue, true},
+		{"bold red", "\033[1m" + Red, true},
+		{"208", "\033[38;5;208m", true},
+		{"#268bd2", "\033[38;2;38;139;210m", true},
+		{"none", "", true},
+		{"", "", false},
+		{"256", "", false},
+		{"#12345", "", false},
+		{"#zzzzzz", "", false},
+		{"chartreuse", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseColorSpec(tt.spec)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseColorSpec(%q) = %q, %v; want %q, %v", tt.spec, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBackground(t *testing.T) {
+	tests := []struct {
+		fg, want string
+	}{
+		{Red, BgRed},
+		{Blue, BgBlue},
+		{"\033[91m", "\033[101m"},
+		{"\033[38;5;167m", "\033[48;5;167m"},
+		{"\033[38;2;1;2;3m", "\033[48;2;1;2;3m"},
+		{"\033[1m\033[91m", "\033[101m"},
+		{DimCode, ""},
+	}
+	for _, tt := range tests {
+		if got := background(tt.fg); got != tt.want {
+			t.Errorf("background(%q) = %q, want %q", tt.fg, got, tt.want)
+		}
+	}
+}
+
+func TestLoadUserTheme(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	dir := filepath.Join(xdg, "claude-statusline", "themes")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "mine"), []byte(`
+# Custom theme
+base=light
+ok=#00ff00
+warn = bold yellow
+critical=not-a-color
+unknown-role=red
+`), 0o644)
+
+	theme, err := LoadTheme("Mine")
+	if err != nil {
+		t.Fatalf("LoadTheme: %v", err)
+	}
+	light := builtinThemes["light"]
+
+	if got := theme[types.RoleOK]; got != "\033[38;2;0;255;0m" {
+		t.Errorf("ok = %q, want truecolor green", got)
+	}
+	if got := theme[types.RoleWarn]; got != "\033[1m"+Yellow {
+		t.Errorf("warn = %q, want bold yellow", got)
+	}
+	if got := theme[types.RoleCritical]; got != light[types.RoleCritical] {
+		t.Errorf("invalid critical spec should inherit from base, got %q", got)
+	}
+	if got := theme[types.RoleDim]; got != light[types.RoleDim] {
+		t.Errorf("dim = %q, want inherited from light", got)
+	}
+	if _, ok := theme[types.Role("unknown-role")]; ok {
+		t.Error("unknown roles should be ignored")
+	}
+}
+
+func TestLoadThemeFallsBackToDefault(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dark := builtinThemes[DefaultTheme]
+
+	for _, name := range []string{"missing", "../config", ""} {
+		theme, err := LoadTheme(name)
+		if name != "" && err == nil {
+			t.Errorf("LoadTheme(%q) should report an error", name)
+		}
+		if theme[types.RoleOK] != dark[types.RoleOK] {
+			t.Errorf("LoadTheme(%q) should fall back to %s", name, DefaultTheme)
+		}
+	}
+}
+
+func TestThemedRenderer(t *testing.T) {
+	theme, _ := LoadTheme("light")
+	r := NewWithTheme(theme, Color16)
+
+	if got := r.Style("x", types.RoleCritical); got != theme[types.RoleCritical]+"x"+Reset {
+		t.Errorf("Style(critical) = %q, want critical role color", got)
+	}
+	if got := r.Dim("x"); got != theme[types.RoleDim]+"x"+Reset {
+		t.Errorf("Dim = %q, want dim role color", got)
+	}
+
+	bar := r.MakeSplitBar(50, 75, cells(8))
+	if !strings.Contains(bar, background(theme[types.RoleBarTime])+"▇") {
+		t.Errorf("split bar overlap should use bar-time background: %q", bar)
+	}
+	if !strings.Contains(bar, theme[types.RoleBarTime]+"▁") {
+		t.Errorf("split bar time-only cells should use bar-time color: %q", bar)
+	}
+	if !strings.Contains(bar, theme[types.RoleBarEmpty]+"░") {
+		t.Errorf("split bar empty cells should use bar-empty color: %q", bar)
+	}
+}
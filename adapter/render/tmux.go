@@ -0,0 +1,188 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Tmux implements the ports.Renderer interface with tmux style codes
+// ("#[fg=green]…#[default]"), for use in status-left/status-right via #().
+type Tmux struct {
+	styles map[types.Role]string // role → tmux style, e.g. "fg=green" or "dim"
+	timeBg string                // bar-time as a background style
+	locale types.Locale
+}
+
+// NewTmux creates a tmux renderer, translating the theme's colors to tmux styles.
+func NewTmux(theme Theme) *Tmux {
+	t := &Tmux{styles: map[types.Role]string{}}
+	for role, seq := range theme {
+		t.styles[role] = tmuxStyle(seq)
+	}
+	t.timeBg = tmuxStyle(background(theme[types.RoleBarTime]))
+	return t
+}
+
+// Style wraps text with the tmux style for role.
+func (t *Tmux) Style(text string, role types.Role) string {
+	return t.wrap(text, t.styles[role])
+}
+
+// Dim applies the dim role.
+func (t *Tmux) Dim(text string) string {
+	return t.Style(text, types.RoleDim)
+}
+
+// MakeBar creates a progress bar with tmux styles.
+func (t *Tmux) MakeBar(percent int, bar types.BarStyle) string {
+	filledGlyph, emptyGlyph := glyphs(bar)
+	filled := clamp(percent, 0, 100) * bar.Width / 100
+	role := usageRole(bar, clamp(percent, 0, 100))
+	return t.wrap(strings.Repeat(filledGlyph, filled), t.styles[role]) +
+		t.wrap(strings.Repeat(emptyGlyph, bar.Width-filled), t.styles[types.RoleBarEmpty])
+}
+
+// timeStyle returns the tmux style of a time-only cell in a split bar style,
+// from the theme like the ANSI split bar time layers.
+func (t *Tmux) timeStyle(style string) string {
+	switch style {
+	case "bg":
+		return joinStyles(t.timeBg, t.styles[types.RoleDim])
+	case "lower-half-dim":
+		return joinStyles(t.styles[types.RoleDim], t.styles[types.RoleBarTime])
+	}
+	return t.styles[types.RoleBarTime]
+}
+
+// MakeSplitBar creates a layered progress bar in the ANSI split bar styles.
+func (t *Tmux) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
+	filledGlyph, emptyGlyph := glyphs(bar)
+	usagePct = clamp(usagePct, 0, 100)
+	usageFilled := usagePct * bar.Width / 100
+	timeFilled := clamp(timePct, 0, 100) * bar.Width / 100
+	usage := t.styles[usageRole(bar, usagePct)]
+
+	thin := bar.Style == "" || bar.Style == "thin"
+	overlapGlyph := filledGlyph
+	if filledGlyph == defaultFilled {
+		overlapGlyph = "▇"
+	}
+	timeStyle := t.timeStyle(bar.Style)
+
+	var b strings.Builder
+	for i := 0; i < bar.Width; i++ {
+		hasUsage := i < usageFilled
+		hasTime := i < timeFilled
+		switch {
+		case hasUsage && hasTime && thin:
+			b.WriteString(t.wrap(overlapGlyph, joinStyles(usage, t.timeBg)))
+		case hasUsage:
+			b.WriteString(t.wrap(filledGlyph, usage))
+		case hasTime:
+			b.WriteString(t.wrap(timeGlyph(bar.Style, emptyGlyph), timeStyle))
+		default:
+			b.WriteString(t.wrap(emptyGlyph, t.styles[types.RoleBarEmpty]))
+		}
+	}
+	return b.String()
+}
+
+// SetLocale sets the number format used by FormatTokens, FormatTokensF and FormatCost.
+func (t *Tmux) SetLocale(l types.Locale) { t.locale = l }
+
+// FormatTokens formats a token count with 0 decimals: "200K".
+func (t *Tmux) FormatTokens(n int) string { return formatTokens(t.locale, n) }
+
+// FormatTokensF formats a token count with 1 decimal: "100.0K".
+func (t *Tmux) FormatTokensF(n int) string { return formatTokensF(t.locale, n) }
+
+// FormatCost formats a cost as "$X.XX".
+func (t *Tmux) FormatCost(f float64) string { return formatCost(t.locale, f) }
+
+// Icon returns the emoji for name, or "" if there is none.
+func (t *Tmux) Icon(name types.Icon) string { return types.EmojiIcons[name] }
+
+func (t *Tmux) wrap(text, style string) string {
+	if style == "" || text == "" {
+		return text
+	}
+	return "#[" + style + "]" + text + "#[default]"
+}
+
+// StripMarkup removes the "#[...]" style markers, leaving the visible text.
+func (t *Tmux) StripMarkup(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "#[")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], ']')
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+func joinStyles(styles ...string) string {
+	var parts []string
+	for _, s := range styles {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+var tmuxColorNames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+var tmuxAttrs = map[int]string{1: "bold", 2: "dim", 3: "italics", 4: "underscore"}
+
+// tmuxStyle translates an ANSI escape sequence into a tmux style list.
+func tmuxStyle(seq string) string {
+	var parts []string
+	for _, part := range strings.Split(seq, "\033[") {
+		params := strings.Split(strings.TrimSuffix(part, "m"), ";")
+		if params[0] == "" {
+			continue
+		}
+		kind := "fg="
+		if params[0] == "48" {
+			kind = "bg="
+		}
+		switch {
+		case len(params) == 5 && (params[0] == "38" || params[0] == "48") && params[1] == "2":
+			r, _ := strconv.Atoi(params[2])
+			g, _ := strconv.Atoi(params[3])
+			b, _ := strconv.Atoi(params[4])
+			parts = append(parts, fmt.Sprintf("%s#%02x%02x%02x", kind, r, g, b))
+		case len(params) == 3 && (params[0] == "38" || params[0] == "48") && params[1] == "5":
+			parts = append(parts, kind+"colour"+params[2])
+		case len(params) == 1:
+			n, err := strconv.Atoi(params[0])
+			if err != nil {
+				continue
+			}
+			switch {
+			case tmuxAttrs[n] != "":
+				parts = append(parts, tmuxAttrs[n])
+			case n >= 30 && n <= 37:
+				parts = append(parts, "fg="+tmuxColorNames[n-30])
+			case n >= 40 && n <= 47:
+				parts = append(parts, "bg="+tmuxColorNames[n-40])
+			case n >= 90 && n <= 97:
+				parts = append(parts, "fg=bright"+tmuxColorNames[n-90])
+			case n >= 100 && n <= 107:
+				parts = append(parts, "bg=bright"+tmuxColorNames[n-100])
+			}
+		}
+	}
+	return strings.Join(parts, ",")
+}
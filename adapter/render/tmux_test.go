@@ -0,0 +1,71 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestTmuxStyle(t *testing.T) {
+	tests := []struct {
+		seq, want string
+	}{
+		{Green, "fg=green"},
+		{DimCode, "dim"},
+		{"\033[1m\033[91m", "bold,fg=brightred"},
+		{"\033[38;5;208m", "fg=colour208"},
+		{"\033[38;2;38;139;210m", "fg=#268bd2"},
+		{BgRed, "bg=red"},
+		{"\033[48;5;167m", "bg=colour167"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := tmuxStyle(tt.seq); got != tt.want {
+			t.Errorf("tmuxStyle(%q) = %q, want %q", tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestTmuxRenderer(t *testing.T) {
+	r := NewTmux(builtinThemes["dark"])
+
+	if got := r.Style("46%", types.RoleOK); got != "#[fg=green]46%#[default]" {
+		t.Errorf("Style = %q", got)
+	}
+	if got := r.Dim("t/m"); got != "#[dim]t/m#[default]" {
+		t.Errorf("Dim = %q", got)
+	}
+	if got := r.MakeBar(50, cells(4)); got != "#[fg=yellow]██#[default]#[dim]░░#[default]" {
+		t.Errorf("MakeBar = %q", got)
+	}
+
+	split := r.MakeSplitBar(25, 50, cells(4))
+	if want := "#[fg=green,bg=red]▇#[default]#[fg=red]▁#[default]#[dim]░#[default]#[dim]░#[default]"; split != want {
+		t.Errorf("MakeSplitBar = %q, want %q", split, want)
+	}
+	if strings.Contains(split, "\033") {
+		t.Errorf("tmux output contains ANSI escapes: %q", split)
+	}
+
+	if got := r.StripMarkup(r.Dim("t/m") + " " + split); got != "t/m ▇▁░░" {
+		t.Errorf("StripMarkup = %q, want the visible text", got)
+	}
+
+	if got := NewTmux(Theme{}).Style("x", types.RoleOK); got != "x" {
+		t.Errorf("unstyled role = %q, want plain text", got)
+	}
+}
+
+func TestThemeHex(t *testing.T) {
+	dark := builtinThemes["dark"]
+	if got := dark.Hex(types.RoleCritical); got != "#cd0000" {
+		t.Errorf("dark critical = %q, want #cd0000", got)
+	}
+	if got := builtinThemes["solarized"].Hex(types.RoleOK); got != "#859900" {
+		t.Errorf("solarized ok = %q, want #859900", got)
+	}
+	if got := dark.Hex(types.RoleDim); got != "" {
+		t.Errorf("dim has no color, got %q", got)
+	}
+}
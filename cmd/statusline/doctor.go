@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	adaptapi "github.com/Benniphx/claude-statusline/adapter/api"
+	"github.com/Benniphx/claude-statusline/adapter/cache"
+	adaptconfig "github.com/Benniphx/claude-statusline/adapter/config"
+	"github.com/Benniphx/claude-statusline/adapter/platform"
+	adaptrender "github.com/Benniphx/claude-statusline/adapter/render"
+	"github.com/Benniphx/claude-statusline/core/compat"
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/model"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/schema"
+	"github.com/Benniphx/claude-statusline/core/segment"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+const doctorUsage = "usage: statusline doctor stdin [--live] [payload.json]   (reads stdin without a file)"
+
+// runDoctor runs a diagnostics subcommand and returns the exit status.
+//
+//	statusline doctor stdin [file]
+//
+// validates a captured stdin payload against the known schema and shows what
+// each segment renders from it. Rate limits come from the payload and the
+// cache only; --live allows the requests the statusline would make.
+func runDoctor(args []string) int {
+	if len(args) == 0 || args[0] != "stdin" {
+		fmt.Fprintln(os.Stderr, doctorUsage)
+		return 2
+	}
+	args = args[1:]
+	live := len(args) > 0 && args[0] == "--live"
+	if live {
+		args = args[1:]
+	}
+	var raw []byte
+	var err error
+	if len(args) > 0 {
+		raw, err = os.ReadFile(args[0])
+	} else {
+		raw, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doctor: %v\n", err)
+		return 1
+	}
+
+	// Schema
+	report, err := schema.Check(raw)
+	if err != nil {
+		fmt.Printf("Schema: invalid JSON: %v\n", err)
+		return 1
+	}
+	if report.Empty() {
+		fmt.Println("Schema: ok")
+	} else {
+		fmt.Printf("Schema: %d unknown, %d missing, %d invalid\n", len(report.Unknown), len(report.Missing), len(report.Invalid))
+		for _, p := range []struct {
+			name string
+			keys []string
+		}{{"unknown", report.Unknown}, {"missing", report.Missing}, {"invalid", report.Invalid}} {
+			for _, key := range p.keys {
+				fmt.Printf("  %-8s %s\n", p.name, key)
+			}
+		}
+	}
+
+	input, err := parseStdin(raw)
+	if err == nil && input.Version != "" {
+		if target := compat.Upgrade(input.Version); target != "" {
+			var missing []string
+			for _, f := range compat.Missing(input.Version) {
+				missing = append(missing, string(f))
+			}
+			fmt.Printf("Claude Code %s: upgrade to %s for %s\n", input.Version, target, strings.Join(missing, ", "))
+		} else if _, known := compat.Supports(input.Version, compat.RateLimits); known {
+			fmt.Printf("Claude Code %s: ok\n", input.Version)
+		}
+	}
+	switch {
+	case err != nil:
+		fmt.Printf("\nInput: does not decode (%v): the statusline shows \"Starting...\"\n", err)
+		return 0
+	case isEmptyInput(input):
+		fmt.Println("\nInput: no model, context window or duration: the statusline shows \"Starting...\"")
+		return 0
+	}
+
+	// Segments, rendered as plain text from the payload. Cache writes are
+	// dropped, so a replayed payload doesn't count toward cost or deltas.
+	plat := platform.Detect()
+	store := readOnlyStore{cache.New()}
+	cfg := adaptconfig.Load()
+	cfg.Version = version
+	creds, _ := plat.GetCredentials()
+	var api ports.APIClient = offlineAPI{}
+	if live {
+		api = adaptapi.NewWithCacheDir(cfg.CacheDir, version)
+	}
+	rc := &ports.RenderContext{
+		Input:       input,
+		Model:       model.Resolve(input.Model.Identifier(), input.Model.DisplayName, model.NewOllamaClient(cfg.CacheDir, store), cfg.CacheDir, cfg),
+		Config:      cfg,
+		Renderer:    adaptrender.NewPlain(),
+		Credentials: creds,
+		Platform:    plat,
+		Store:       store,
+		API:         api,
+	}
+	configured := map[string]bool{}
+	for _, names := range layout.Order(cfg, creds.HasOAuth()) {
+		for _, name := range names {
+			configured[name] = true
+		}
+	}
+
+	if live {
+		fmt.Println("\nRate limits: from stdin, the cache or the API")
+	} else {
+		fmt.Println("\nRate limits: from stdin and the cache only (--live to query the API)")
+	}
+
+	fmt.Println("\nSegments (* = in your layout):")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range segment.Names() {
+		mark := " "
+		if configured[name] {
+			mark = "*"
+		}
+		state, text := "hidden", ""
+		if sections := segment.Build([]string{name}, rc); len(sections) == 1 && sections[0].Text != "" {
+			state, text = "shown", strings.ReplaceAll(sections[0].Text, "\n", " ")
+		}
+		fmt.Fprintf(w, "  %s %s\t%s\t%s\n", mark, name, state, text)
+	}
+	w.Flush()
+	return 0
+}
+
+// readOnlyStore reads from the cache but discards writes.
+type readOnlyStore struct {
+	ports.CacheStore
+}
+
+func (readOnlyStore) AtomicWrite(path string, data []byte) error { return nil }
+func (readOnlyStore) WriteFile(path string, data []byte) error   { return nil }
+func (readOnlyStore) AppendFile(path string, data []byte) error  { return nil }
+func (readOnlyStore) CleanOld(dir, pattern, keep string) error   { return nil }
+
+// offlineAPI stands in for the API client unless doctor runs with --live.
+type offlineAPI struct{}
+
+var errOffline = errors.New("offline: run doctor with --live to query the API")
+
+func (offlineAPI) FetchRateLimits(token string) (*types.RateLimitResponse, error) {
+	return nil, errOffline
+}
+func (offlineAPI) FetchLatestRelease(repo string) (string, error) { return "", errOffline }
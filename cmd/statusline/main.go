@@ -6,20 +6,21 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	adaptapi "github.com/Benniphx/claude-statusline/adapter/api"
 	"github.com/Benniphx/claude-statusline/adapter/cache"
 	adaptconfig "github.com/Benniphx/claude-statusline/adapter/config"
 	"github.com/Benniphx/claude-statusline/adapter/platform"
 	adaptrender "github.com/Benniphx/claude-statusline/adapter/render"
-	"github.com/Benniphx/claude-statusline/core/agents"
-	corecontext "github.com/Benniphx/claude-statusline/core/context"
-	"github.com/Benniphx/claude-statusline/core/cost"
+	"github.com/Benniphx/claude-statusline/core/layout"
 	"github.com/Benniphx/claude-statusline/core/model"
-	"github.com/Benniphx/claude-statusline/core/ollama"
+	"github.com/Benniphx/claude-statusline/core/ports"
 	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/schema"
+	"github.com/Benniphx/claude-statusline/core/segment"
+	"github.com/Benniphx/claude-statusline/core/status"
 	"github.com/Benniphx/claude-statusline/core/types"
-	"github.com/Benniphx/claude-statusline/core/update"
 )
 
 var version = "dev"
@@ -34,8 +35,15 @@ func main() {
 		case "setup":
 			runSetup()
 			return
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:]))
 		}
 	}
+	format, err := parseFormat(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
 	// Wire adapters
 	plat := platform.Detect()
@@ -43,99 +51,155 @@ func main() {
 	cfg := adaptconfig.Load()
 	cfg.Version = version
 	api := adaptapi.NewWithCacheDir(cfg.CacheDir, version)
-	rend := adaptrender.New()
-
-	// Separator: 2 spaces + dim │ + 2 spaces (matching bash)
-	sep := "  " + rend.Dim("│") + "  "
+	rend := newRenderer(cfg, format)
 
 	// Parse stdin
-	input, err := parseStdin()
-	if err != nil || isEmptyInput(input) {
+	raw, _ := io.ReadAll(os.Stdin)
+	if cfg.Diagnostics && len(strings.TrimSpace(string(raw))) > 0 {
+		schema.Log(raw, cfg, store, time.Now())
+	}
+	input, err := parseStdin(raw)
+	withInput := err == nil && !isEmptyInput(input)
+	if !withInput && format == formatTerminal {
 		// Empty input: dim "Starting..." + optional cached 5h rate
-		fmt.Print(rend.Dim("Starting..."))
+		sections := []layout.Section{{Text: rend.Dim("Starting...")}}
 		creds, credErr := plat.GetCredentials()
 		if credErr == nil && creds.HasOAuth() {
 			rateStr := ratelimit.Render(creds, cfg, plat, store, api, rend)
-			if rateStr != "" {
-				fmt.Print(sep + rateStr)
-			}
+			sections = append(sections, layout.Section{Name: layout.FiveHour, Text: rateStr})
 		}
+		fmt.Print(layout.Assemble(sections, rend))
 		return
 	}
 
 	// Resolve model info
 	ollamaClient := model.NewOllamaClient(cfg.CacheDir, store)
-	modelInfo := model.Resolve(input.Model.ModelID, input.Model.DisplayName, ollamaClient, cfg.CacheDir, cfg)
-
-	// Calculate context display
-	ctxDisplay := corecontext.Calculate(input, modelInfo, cfg)
-
-	// Get context-based color (used for model name)
-	ctxColor := adaptrender.ColorForPercent(ctxDisplay.PercentUsed)
+	modelInfo := model.Resolve(input.Model.Identifier(), input.Model.DisplayName, ollamaClient, cfg.CacheDir, cfg)
 
 	// Get credentials
 	creds, _ := plat.GetCredentials()
 
-	// Build sections
-	var sections []string
-
-	// 1. Model name (colored by context %) + agent count
-	modelSection := rend.Color(modelInfo.ShortName, ctxColor)
-	agentInfo := agents.Count()
-	if agentInfo.HasSubagents {
-		modelSection += rend.Dim(fmt.Sprintf(" (%d)", agentInfo.Total))
+	rc := &ports.RenderContext{
+		Input:       input,
+		Model:       modelInfo,
+		Config:      cfg,
+		Renderer:    rend,
+		Credentials: creds,
+		Platform:    plat,
+		Store:       store,
+		API:         api,
 	}
-	sections = append(sections, modelSection)
 
-	// 2. Context window
-	ctxSection := corecontext.Render(ctxDisplay, cfg, rend)
-	sections = append(sections, ctxSection)
+	// JSON needs the computed data only, not the rendered segments
+	if format == formatJSON {
+		data, _ := json.MarshalIndent(status.Collect(rc, withInput).Document(time.Now()), "", "  ")
+		fmt.Println(string(data))
+		return
+	}
 
-	// 3. Rate limit sections (OAuth) or Cost sections (API key)
-	if creds.HasOAuth() {
-		rate := ratelimit.RenderSections(input, creds, cfg, plat, store, api, rend, modelInfo)
-		sections = append(sections, rate.FiveHour, rate.Burn, rate.SevenDay)
-	} else {
-		cs := cost.RenderSections(input, cfg, plat, store, rend, modelInfo)
-		sections = append(sections, cs.Session, cs.Daily, cs.Burn)
+	// Without Claude Code input (status bars), only account-wide segments apply
+	order := layout.Order(cfg, creds.HasOAuth())
+	if !withInput {
+		order = [][]string{layout.Standalone(creds.HasOAuth())}
 	}
 
-	// 4. Duration
-	durationSection := rend.Dim(fmt.Sprintf("%dm", ctxDisplay.DurationMin))
+	// Render registered segments in configured order, one output line per
+	// layout line; unknown names are ignored and each line is width-budgeted alone
+	var lines []string
+	for _, names := range order {
+		sections := layout.Fit(segment.Build(names, rc), rend, cfg.MaxWidth)
+		if line := layout.Assemble(sections, rend); line != "" {
+			lines = append(lines, line)
+		}
+	}
 
-	// 5. Lines info (appended to duration with 1-space separator)
-	linesInfo := ""
-	if ctxDisplay.LinesAdded > 0 || ctxDisplay.LinesRemoved > 0 {
-		linesInfo = fmt.Sprintf(" %s %s+%d%s/%s-%d%s",
-			rend.Dim("│"),
-			adaptrender.Green, ctxDisplay.LinesAdded, adaptrender.Reset,
-			adaptrender.Red, ctxDisplay.LinesRemoved, adaptrender.Reset)
+	switch format {
+	case formatTmux:
+		// tmux status lines are single-line
+		fmt.Print(strings.Join(lines, " "))
+	case formatWaybar, formatI3Blocks:
+		text := strings.Join(lines, "  │  ")
+		snap := status.Collect(rc, withInput)
+		var out any = snap.Waybar(text, rend)
+		if format == formatI3Blocks {
+			color := ""
+			if !cfg.NoColor {
+				theme, _ := adaptrender.LoadTheme(cfg.Theme)
+				color = theme.Hex(snap.Level())
+			}
+			out = snap.I3Block(text, color, rend)
+		}
+		data, _ := json.Marshal(out)
+		fmt.Println(string(data))
+	default:
+		fmt.Print(strings.Join(lines, "\n"))
 	}
+}
+
+// Output formats selected with --format.
+const (
+	formatTerminal = "terminal" // Claude Code statusline (default)
+	formatTmux     = "tmux"
+	formatWaybar   = "waybar"
+	formatI3Blocks = "i3blocks"
+	formatJSON     = "json"
+)
 
-	// 6. Ollama stats (only if stats file exists and is fresh)
-	ollamaSection := ""
-	if stats, err := ollama.ReadStats(ollama.StatsPath); err == nil {
-		if rendered := ollama.Render(stats); rendered != "" {
-			ollamaSection = sep + rend.Dim(rendered)
+// parseFormat reads "--format X" or "--format=X" from the arguments.
+func parseFormat(args []string) (string, error) {
+	format := formatTerminal
+	for i := 0; i < len(args); i++ {
+		value, ok := strings.CutPrefix(args[i], "--format=")
+		if args[i] == "--format" {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("--format requires a value: tmux, waybar, i3blocks or json")
+			}
+			i++
+			value, ok = args[i], true
+		}
+		if !ok {
+			continue
+		}
+		switch value {
+		case formatTerminal, formatTmux, formatWaybar, formatI3Blocks, formatJSON:
+			format = value
+		default:
+			return "", fmt.Errorf("unknown format %q: want tmux, waybar, i3blocks or json", value)
 		}
 	}
+	return format, nil
+}
 
-	// 7. Update notice
-	updateNotice := update.Render(cfg.Version, cfg.CacheDir, store, api, rend)
+// newRenderer selects the renderer: plain for NO_COLOR and JSON status bars,
+// tmux styles for tmux, else the configured style.
+func newRenderer(cfg types.Config, format string) ports.Renderer {
+	r := selectRenderer(cfg, format)
+	if l, ok := r.(localized); ok {
+		l.SetLocale(cfg.Locale)
+	}
+	return r
+}
 
-	// Assemble: sections joined by sep, then duration+lines+ollama+update
-	sections = append(sections, durationSection)
-	output := strings.Join(sections, sep)
-	output += linesInfo + ollamaSection + updateNotice
-	fmt.Print(output)
+// localized is implemented by renderers with a configurable number format.
+type localized interface {
+	SetLocale(types.Locale)
 }
 
-func parseStdin() (types.Input, error) {
-	data, err := io.ReadAll(os.Stdin)
-	if err != nil {
-		return types.Input{}, err
+func selectRenderer(cfg types.Config, format string) ports.Renderer {
+	if cfg.NoColor || cfg.Renderer == "plain" || format == formatWaybar || format == formatI3Blocks {
+		return adaptrender.NewPlain()
 	}
+	theme, _ := adaptrender.LoadTheme(cfg.Theme) // falls back to the default theme
+	if format == formatTmux {
+		return adaptrender.NewTmux(theme)
+	}
+	if cfg.Renderer == "powerline" {
+		return adaptrender.NewPowerline(theme, adaptrender.DetectColorDepth())
+	}
+	return adaptrender.NewWithTheme(theme, adaptrender.DetectColorDepth())
+}
 
+func parseStdin(data []byte) (types.Input, error) {
 	if len(strings.TrimSpace(string(data))) == 0 {
 		return types.Input{}, fmt.Errorf("empty input")
 	}
@@ -149,7 +213,7 @@ func parseStdin() (types.Input, error) {
 }
 
 func isEmptyInput(input types.Input) bool {
-	return input.Model.ModelID == "" && input.Model.DisplayName == "" &&
+	return input.Model.Identifier() == "" && input.Model.DisplayName == "" &&
 		input.ContextWindow.ContextWindowSize == 0 &&
 		input.Cost.TotalDurationMS == 0
 }
@@ -0,0 +1,74 @@
+// Package compat maps Claude Code versions to the stdin fields they provide,
+// so data sources are chosen by version rather than by what a payload happens
+// to contain.
+package compat
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/update"
+)
+
+// Feature is a stdin field introduced by a Claude Code version.
+type Feature string
+
+const (
+	// RateLimits is the native 5h/7d usage (rate_limits), replacing the /api/oauth/usage poll.
+	RateLimits Feature = "rate_limits"
+)
+
+// Since holds the first Claude Code version providing each feature.
+var Since = map[Feature]string{
+	RateLimits: "2.1.80",
+}
+
+// Supports reports whether Claude Code version provides feature. known is
+// false when the version is missing or not numeric ("1.2.3"); callers then
+// fall back to what the payload contains.
+func Supports(version string, f Feature) (supported, known bool) {
+	since, ok := Since[f]
+	if !ok || !valid(version) {
+		return false, false
+	}
+	return !update.GreaterThan(since, version), true
+}
+
+// Missing returns the features a known version lacks, sorted by name.
+func Missing(version string) []Feature {
+	var missing []Feature
+	for f := range Since {
+		if supported, known := Supports(version, f); known && !supported {
+			missing = append(missing, f)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return missing
+}
+
+// Upgrade returns the Claude Code version providing every feature a known
+// version lacks, or "" when it is up to date (or unknown).
+func Upgrade(version string) string {
+	var target string
+	for _, f := range Missing(version) {
+		if target == "" || update.GreaterThan(Since[f], target) {
+			target = Since[f]
+		}
+	}
+	return target
+}
+
+// valid reports whether version is dotted numbers, e.g. "2.1.80" or "v2.1".
+func valid(version string) bool {
+	version = strings.TrimPrefix(version, "v")
+	if version == "" {
+		return false
+	}
+	for _, part := range strings.Split(version, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package compat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		version       string
+		wantSupported bool
+		wantKnown     bool
+	}{
+		{"2.1.80", true, true},
+		{"2.1.81", true, true},
+		{"2.2", true, true},
+		{"v3.0.0", true, true},
+		{"2.1.79", false, true},
+		{"1.0.128", false, true},
+		{"", false, false},
+		{"dev", false, false},
+		{"2.1.80-beta", false, false},
+	}
+	for _, tt := range tests {
+		supported, known := Supports(tt.version, RateLimits)
+		if supported != tt.wantSupported || known != tt.wantKnown {
+			t.Errorf("Supports(%q) = %v, %v; want %v, %v", tt.version, supported, known, tt.wantSupported, tt.wantKnown)
+		}
+	}
+
+	if _, known := Supports("2.1.80", Feature("unknown")); known {
+		t.Error("a feature missing from the table should be unknown")
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	if got := Upgrade("2.1.70"); got != "2.1.80" {
+		t.Errorf("Upgrade(2.1.70) = %q, want 2.1.80", got)
+	}
+	if got := Missing("2.1.70"); !reflect.DeepEqual(got, []Feature{RateLimits}) {
+		t.Errorf("Missing(2.1.70) = %v", got)
+	}
+	for _, v := range []string{"2.1.80", "", "dev"} {
+		if got := Upgrade(v); got != "" {
+			t.Errorf("Upgrade(%q) = %q, want none", v, got)
+		}
+	}
+}
@@ -77,15 +77,12 @@ func Calculate(input types.Input, model types.ModelInfo, cfg types.Config) types
 // Render produces the context section string (bar + percentage + tokens only).
 // Does NOT include model name, duration, or lines — those are assembled by main.go.
 func Render(display types.ContextDisplay, cfg types.Config, r ports.Renderer) string {
-	bar := r.MakeBar(display.PercentUsed, 8)
+	bs := cfg.ContextBar
+	bs.Levels = cfg.Thresholds.Context
+	bar := r.MakeBar(display.PercentUsed, bs)
 
 	// Percentage or "--" for initial state
-	var pctStr string
-	if display.IsInitial {
-		pctStr = r.Colorize("--", display.PercentUsed)
-	} else {
-		pctStr = r.Colorize(fmt.Sprintf("%d%%", display.PercentUsed), display.PercentUsed)
-	}
+	pctStr := percent(display, cfg, r)
 
 	// Warning threshold
 	warning := ""
@@ -97,5 +94,28 @@ func Render(display types.ContextDisplay, cfg types.Config, r ports.Renderer) st
 	tokens := r.FormatTokensF(display.TokensUsed)
 	total := r.FormatTokens(display.TokensTotal)
 
-	return fmt.Sprintf("Ctx: %s %s%s %s", bar, pctStr, warning, r.Dim(fmt.Sprintf("(%s/%s)", tokens, total)))
+	return fmt.Sprintf("%s %s %s%s %s", label(r), bar, pctStr, warning, r.Dim(fmt.Sprintf("(%s/%s)", tokens, total)))
+}
+
+// RenderCompact produces the short context section without bar or token counts: "Ctx: 30%".
+func RenderCompact(display types.ContextDisplay, cfg types.Config, r ports.Renderer) string {
+	return label(r) + " " + percent(display, cfg, r)
+}
+
+// percent renders the usage percentage, or "--" in the initial state,
+// colored by the context threshold.
+func percent(display types.ContextDisplay, cfg types.Config, r ports.Renderer) string {
+	role := cfg.Thresholds.Context.Role(float64(display.PercentUsed))
+	if display.IsInitial {
+		return r.Style("--", role)
+	}
+	return r.Style(fmt.Sprintf("%d%%", display.PercentUsed), role)
+}
+
+// label is the context icon, or "Ctx:" when the icon set has none.
+func label(r ports.Renderer) string {
+	if icon := r.Icon(types.IconContext); icon != "" {
+		return icon
+	}
+	return "Ctx:"
 }
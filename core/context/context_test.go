@@ -229,6 +229,49 @@ func TestRenderWithWarning(t *testing.T) {
 	}
 }
 
+func TestRenderCompact(t *testing.T) {
+	r := render.New()
+	cfg := types.DefaultConfig()
+	model := types.ModelInfo{DefaultContext: 200000}
+
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 90000},
+		},
+		Cost: types.Cost{TotalDurationMS: 300000},
+	}
+	result := RenderCompact(Calculate(input, model, cfg), cfg, r)
+	if !strings.Contains(result, "Ctx: ") || !strings.Contains(result, "45%") {
+		t.Errorf("compact should show 'Ctx: 45%%', got: %s", result)
+	}
+	if strings.Contains(result, "█") || strings.Contains(result, "200K") {
+		t.Errorf("compact should omit bar and tokens, got: %s", result)
+	}
+
+	initial := RenderCompact(Calculate(types.Input{}, model, cfg), cfg, r)
+	if !strings.Contains(initial, "--") {
+		t.Errorf("initial compact should show '--', got: %s", initial)
+	}
+}
+
+func TestRenderContextThreshold(t *testing.T) {
+	r := render.New()
+	display := types.ContextDisplay{PercentUsed: 85, TokensUsed: 170000, TokensTotal: 200000}
+
+	cfg := types.DefaultConfig()
+	if got := Render(display, cfg, r); !strings.Contains(got, render.Red+"85%") {
+		t.Errorf("85%% with default thresholds should be red, got: %q", got)
+	}
+
+	// Bar and percentage both follow the configured threshold
+	cfg.Thresholds.Context = types.Threshold{Warn: 90, Crit: 95}
+	got := Render(display, cfg, r)
+	if !strings.Contains(got, render.Green+"85%") || !strings.Contains(got, render.Green+"█") {
+		t.Errorf("85%% with 90/95 thresholds should be green, got: %q", got)
+	}
+}
+
 func ptrFloat(f float64) *float64 {
 	return &f
 }
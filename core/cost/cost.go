@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Benniphx/claude-statusline/adapter/render"
 	"github.com/Benniphx/claude-statusline/core/ports"
 	"github.com/Benniphx/claude-statusline/core/types"
 )
@@ -21,7 +20,7 @@ type CostSections struct {
 
 // Track computes session and daily cost using delta or replace accounting.
 func Track(input types.Input, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore) types.CostDisplay {
-	sessionID, stable := ResolveSession(plat)
+	sessionID, stable := ResolveSession(input, plat)
 	cost := input.Cost.TotalCostUSD
 
 	today := time.Now().Format("2006-01-02")
@@ -130,14 +129,19 @@ func sumTracker(path string, store ports.CacheStore) float64 {
 	return total
 }
 
-// RenderSections produces the three cost display sections for assembly by main.go.
-func RenderSections(input types.Input, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, r ports.Renderer, modelInfo types.ModelInfo) CostSections {
+// State holds the computed cost data shared by the session, daily and burn sections.
+type State struct {
+	Display  types.CostDisplay
+	LocalTPM int // Cost-normalized tokens per minute (0 = not enough data)
+	Norm     types.CostNorm
+}
+
+// Compute tracks session and daily cost and derives the local burn rate.
+func Compute(input types.Input, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, modelInfo types.ModelInfo) State {
 	display := Track(input, cfg, plat, store)
 
 	// Cost normalization
 	cn := types.ResolveCostNorm(cfg, modelInfo)
-	costMult := cn.Mult
-	costPrefix := cn.Prefix
 
 	// Local burn rate
 	var localTPM int
@@ -149,53 +153,63 @@ func RenderSections(input types.Input, cfg types.Config, plat ports.PlatformInfo
 			totalTokens = input.ContextWindow.TotalInputTokens + input.ContextWindow.TotalOutputTokens
 		}
 		minutes := float64(input.Cost.TotalDurationMS) / 60000.0
-		localTPM = int(math.Round(float64(totalTokens) / minutes * costMult))
-	}
-	_ = costPrefix // used below in burn display
-
-	// Session cost with color thresholds: <$0.50 green, <$2.00 yellow, ≥$2.00 red
-	sessionColor := costColor(display.SessionCost, 0.50, 2.00)
-	sessionStr := fmt.Sprintf("💰 %s", r.Color(fmt.Sprintf("$%.2f", display.SessionCost), sessionColor))
-
-	// Daily cost with color thresholds: <$5 green, <$20 yellow, ≥$20 red
-	dailyColor := costColor(display.DailyCost, 5.00, 20.00)
-	dailyStr := fmt.Sprintf("📅 %s", r.Color(fmt.Sprintf("$%.2f", display.DailyCost), dailyColor))
-
-	// Burn: "TPM t/m $X.XX/h" or "--"
-	var burnStr string
-	if localTPM > 0 {
-		tpmFmt := costPrefix + r.FormatTokensF(localTPM)
-		burnColor := costColor(display.CostPerHour, 1.00, 5.00)
-		burnStr = fmt.Sprintf("🔥 %s %s %s%s",
-			r.Color(tpmFmt, render.Magenta),
-			r.Dim("t/m"),
-			r.Color(fmt.Sprintf("$%.2f", display.CostPerHour), burnColor),
-			r.Dim("/h"))
-	} else {
-		burnStr = "🔥 " + r.Dim("--")
+		localTPM = int(math.Round(float64(totalTokens) / minutes * cn.Mult))
 	}
 
+	return State{Display: display, LocalTPM: localTPM, Norm: cn}
+}
+
+// RenderSections produces the three cost display sections for assembly by main.go.
+func RenderSections(input types.Input, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, r ports.Renderer, modelInfo types.ModelInfo) CostSections {
+	st := Compute(input, cfg, plat, store, modelInfo)
 	return CostSections{
-		Session: sessionStr,
-		Daily:   dailyStr,
-		Burn:    burnStr,
+		Session: RenderSession(st, cfg, r),
+		Daily:   RenderDaily(st, cfg, r),
+		Burn:    RenderBurn(st, cfg, r),
 	}
 }
 
+// RenderSession produces the session cost section, colored by the session
+// threshold (default <$0.50 green, <$2.00 yellow, ≥$2.00 red).
+func RenderSession(st State, cfg types.Config, r ports.Renderer) string {
+	sessionRole := cfg.Thresholds.Session.Role(st.Display.SessionCost)
+	return fmt.Sprintf("%s %s", r.Icon(types.IconCost), r.Style(r.FormatCost(st.Display.SessionCost), sessionRole))
+}
+
+// RenderDaily produces the daily cost section, colored by the daily
+// threshold (default <$5 green, <$20 yellow, ≥$20 red).
+func RenderDaily(st State, cfg types.Config, r ports.Renderer) string {
+	dailyRole := cfg.Thresholds.Daily.Role(st.Display.DailyCost)
+	return fmt.Sprintf("%s %s", r.Icon(types.IconCalendar), r.Style(r.FormatCost(st.Display.DailyCost), dailyRole))
+}
+
+// RenderBurn produces the burn section: "TPM t/m $X.XX/h" or "--".
+func RenderBurn(st State, cfg types.Config, r ports.Renderer) string {
+	if st.LocalTPM <= 0 {
+		return r.Icon(types.IconFlame) + " " + r.Dim("--")
+	}
+	tpmFmt := st.Norm.Prefix + r.FormatTokensF(st.LocalTPM)
+	burnRole := cfg.Thresholds.PerHour.Role(st.Display.CostPerHour)
+	return fmt.Sprintf("%s %s %s %s%s",
+		r.Icon(types.IconFlame),
+		r.Style(tpmFmt, types.RoleBurn),
+		r.Dim("t/m"),
+		r.Style(r.FormatCost(st.Display.CostPerHour), burnRole),
+		r.Dim("/h"))
+}
+
+// RenderBurnCompact produces the burn section as cost per hour only: "🔥 $1.20/h".
+func RenderBurnCompact(st State, cfg types.Config, r ports.Renderer) string {
+	if st.LocalTPM <= 0 {
+		return r.Icon(types.IconFlame) + " " + r.Dim("--")
+	}
+	burnRole := cfg.Thresholds.PerHour.Role(st.Display.CostPerHour)
+	return r.Icon(types.IconFlame) + " " + r.Style(r.FormatCost(st.Display.CostPerHour), burnRole) + r.Dim("/h")
+}
+
 // Render produces the full cost string (legacy compatibility).
 func Render(input types.Input, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, r ports.Renderer, modelInfo types.ModelInfo) string {
 	sections := RenderSections(input, cfg, plat, store, r, modelInfo)
 	sep := "  " + r.Dim("│") + "  "
 	return sections.Session + sep + sections.Daily + sep + sections.Burn
 }
-
-func costColor(cost, warnThreshold, critThreshold float64) string {
-	switch {
-	case cost < warnThreshold:
-		return render.Green
-	case cost < critThreshold:
-		return render.Yellow
-	default:
-		return render.Red
-	}
-}
@@ -55,6 +55,11 @@ func (m *mockCache) WriteFile(path string, data []byte) error {
 	m.files[path] = data
 	return nil
 }
+
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
 func (m *mockCache) FileMTime(path string) (time.Time, error) {
 	return time.Now(), nil
 }
@@ -177,44 +182,44 @@ func TestRenderCost(t *testing.T) {
 
 type mockRenderer struct{}
 
-func (m *mockRenderer) Colorize(text string, percent int) string { return text }
-func (m *mockRenderer) Color(text, color string) string          { return text }
+func (m *mockRenderer) Style(text string, role types.Role) string         { return text }
 func (m *mockRenderer) Dim(text string) string                   { return text }
-func (m *mockRenderer) MakeBar(percent, width int) string                  { return "[bar]" }
-func (m *mockRenderer) MakeSplitBar(usagePct, timePct, width int) string   { return "[split]" }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                  { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string   { return "[split]" }
 func (m *mockRenderer) FormatTokens(n int) string                          { return fmt.Sprintf("%d", n) }
 func (m *mockRenderer) FormatTokensF(n int) string               { return fmt.Sprintf("%d", n) }
 func (m *mockRenderer) FormatCost(f float64) string              { return fmt.Sprintf("$%.2f", f) }
+func (m *mockRenderer) Icon(name types.Icon) string              { return types.EmojiIcons[name] }
 
 func TestCostColor(t *testing.T) {
 	tests := []struct {
-		name      string
-		cost      float64
-		warn      float64
-		crit      float64
-		wantColor string
+		name     string
+		cost     float64
+		warn     float64
+		crit     float64
+		wantRole types.Role
 	}{
 		// Session thresholds: <$0.50 green, <$2.00 yellow, >=$2.00 red
-		{"session_green", 0.15, 0.50, 2.00, "\033[32m"},
-		{"session_yellow", 1.25, 0.50, 2.00, "\033[33m"},
-		{"session_red", 8.50, 0.50, 2.00, "\033[31m"},
-		{"session_boundary_warn", 0.50, 0.50, 2.00, "\033[33m"},
-		{"session_boundary_crit", 2.00, 0.50, 2.00, "\033[31m"},
+		{"session_green", 0.15, 0.50, 2.00, types.RoleOK},
+		{"session_yellow", 1.25, 0.50, 2.00, types.RoleWarn},
+		{"session_red", 8.50, 0.50, 2.00, types.RoleCritical},
+		{"session_boundary_warn", 0.50, 0.50, 2.00, types.RoleWarn},
+		{"session_boundary_crit", 2.00, 0.50, 2.00, types.RoleCritical},
 		// Daily thresholds: <$5 green, <$20 yellow, >=$20 red
-		{"daily_green", 3.50, 5.00, 20.00, "\033[32m"},
-		{"daily_yellow", 12.00, 5.00, 20.00, "\033[33m"},
-		{"daily_red", 25.00, 5.00, 20.00, "\033[31m"},
+		{"daily_green", 3.50, 5.00, 20.00, types.RoleOK},
+		{"daily_yellow", 12.00, 5.00, 20.00, types.RoleWarn},
+		{"daily_red", 25.00, 5.00, 20.00, types.RoleCritical},
 		// Burn thresholds: <$1 green, <$5 yellow, >=$5 red
-		{"burn_green", 0.50, 1.00, 5.00, "\033[32m"},
-		{"burn_yellow", 3.00, 1.00, 5.00, "\033[33m"},
-		{"burn_red", 12.00, 1.00, 5.00, "\033[31m"},
+		{"burn_green", 0.50, 1.00, 5.00, types.RoleOK},
+		{"burn_yellow", 3.00, 1.00, 5.00, types.RoleWarn},
+		{"burn_red", 12.00, 1.00, 5.00, types.RoleCritical},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := costColor(tt.cost, tt.warn, tt.crit)
-			if got != tt.wantColor {
-				t.Errorf("costColor(%f, %f, %f) = %q, want %q", tt.cost, tt.warn, tt.crit, got, tt.wantColor)
+			got := types.LevelRole(tt.cost, tt.warn, tt.crit)
+			if got != tt.wantRole {
+				t.Errorf("LevelRole(%f, %f, %f) = %q, want %q", tt.cost, tt.warn, tt.crit, got, tt.wantRole)
 			}
 		})
 	}
@@ -384,7 +389,7 @@ func TestMultipleSessionsDailyAccumulation(t *testing.T) {
 
 func TestResolveSession(t *testing.T) {
 	plat := &mockPlatform{sessionID: "test-session", stable: true}
-	id, stable := ResolveSession(plat)
+	id, stable := ResolveSession(types.Input{}, plat)
 	if id != "test-session" {
 		t.Errorf("id = %q, want %q", id, "test-session")
 	}
@@ -393,11 +398,59 @@ func TestResolveSession(t *testing.T) {
 	}
 
 	plat2 := &mockPlatform{sessionID: "unstable-1", stable: false}
-	id2, stable2 := ResolveSession(plat2)
+	id2, stable2 := ResolveSession(types.Input{}, plat2)
 	if id2 != "unstable-1" {
 		t.Errorf("id = %q, want %q", id2, "unstable-1")
 	}
 	if stable2 {
 		t.Error("stable = true, want false")
 	}
+
+	// session_id from stdin wins over the platform
+	id3, stable3 := ResolveSession(types.Input{SessionID: "abc-123"}, plat2)
+	if id3 != "abc-123" || !stable3 {
+		t.Errorf("stdin session = %q (stable %v), want abc-123 (stable)", id3, stable3)
+	}
+
+	// IDs that are unsafe in file names fall back to the platform
+	id4, _ := ResolveSession(types.Input{SessionID: "../x"}, plat)
+	if id4 != "test-session" {
+		t.Errorf("unsafe session_id should fall back, got %q", id4)
+	}
+}
+
+// roleRenderer marks styled text with its role: "<warn>$1.25".
+type roleRenderer struct{ mockRenderer }
+
+func (r *roleRenderer) Style(text string, role types.Role) string { return "<" + string(role) + ">" + text }
+
+func TestCustomThresholds(t *testing.T) {
+	st := State{Display: types.CostDisplay{SessionCost: 3.00, DailyCost: 30.00, CostPerHour: 6.00}, LocalTPM: 1000}
+	r := &roleRenderer{}
+
+	// Defaults: all three are critical
+	cfg := types.DefaultConfig()
+	if got := RenderSession(st, cfg, r); !strings.Contains(got, "<critical>$3.00") {
+		t.Errorf("default session = %q, want critical", got)
+	}
+
+	// Larger budgets: the same costs are ok or warn
+	cfg.Thresholds.Session = types.Threshold{Warn: 5, Crit: 10}
+	cfg.Thresholds.Daily = types.Threshold{Warn: 25, Crit: 100}
+	cfg.Thresholds.PerHour = types.Threshold{Warn: 10, Crit: 20}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"session", RenderSession(st, cfg, r), "<ok>$3.00"},
+		{"daily", RenderDaily(st, cfg, r), "<warn>$30.00"},
+		{"burn", RenderBurn(st, cfg, r), "<ok>$6.00"},
+		{"burn compact", RenderBurnCompact(st, cfg, r), "<ok>$6.00"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.got, tt.want) {
+			t.Errorf("%s = %q, want it to contain %q", tt.name, tt.got, tt.want)
+		}
+	}
 }
@@ -1,10 +1,18 @@
 package cost
 
 import (
+	"strings"
+
 	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
 )
 
 // ResolveSession returns a session ID and whether it's stable.
-func ResolveSession(plat ports.PlatformInfo) (string, bool) {
+// The session_id from Claude Code is preferred; without it (older versions),
+// the platform derives one from the environment or process tree.
+func ResolveSession(input types.Input, plat ports.PlatformInfo) (string, bool) {
+	if id := input.SessionID; id != "" && !strings.ContainsAny(id, `/\`) && id != "." && id != ".." {
+		return id, true
+	}
 	return plat.GetStableSessionID()
 }
@@ -0,0 +1,91 @@
+package delta
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Frame holds the session totals seen by one render.
+type Frame struct {
+	Context int     `json:"context"` // Tokens in the context window
+	Output  int     `json:"output"`  // Output tokens of the session
+	Cost    float64 `json:"cost"`    // Session cost in USD
+}
+
+// Delta is the change between two frames.
+type Delta struct {
+	Context int     `json:"context"` // Tokens added to the context (negative after compaction)
+	Output  int     `json:"output"`
+	Cost    float64 `json:"cost"`
+}
+
+// IsZero reports whether nothing changed.
+func (d Delta) IsZero() bool {
+	return d.Context == 0 && d.Output == 0 && math.Abs(d.Cost) < 0.005
+}
+
+// snapshot is the per-session state: the previous frame and the last change.
+type snapshot struct {
+	Frame Frame `json:"frame"`
+	Delta Delta `json:"delta"`
+}
+
+// Track compares frame with the previous frame of the session and returns the
+// most recent change. Renders where nothing changed keep the previous change,
+// so the delta stays visible until the next turn. The first frame of a session,
+// or one after the session cost went down (a new or cleared session), has a zero delta.
+func Track(frame Frame, sessionID string, cfg types.Config, store ports.CacheStore) Delta {
+	path := fmt.Sprintf("%s/claude_delta_%s.json", cfg.CacheDir, sessionID)
+
+	var prev snapshot
+	data, err := store.ReadFile(path)
+	known := err == nil && json.Unmarshal(data, &prev) == nil
+	if known && frame == prev.Frame {
+		return prev.Delta
+	}
+
+	next := snapshot{Frame: frame}
+	if known && frame.Cost >= prev.Frame.Cost {
+		next.Delta = Delta{
+			Context: frame.Context - prev.Frame.Context,
+			Output:  frame.Output - prev.Frame.Output,
+			Cost:    frame.Cost - prev.Frame.Cost,
+		}
+	}
+	if raw, err := json.Marshal(next); err == nil {
+		store.AtomicWrite(path, raw)
+	}
+	return next.Delta
+}
+
+// Render produces the delta section: "Δ +12.4K · 850 out · $0.08", or ""
+// without a change. Zero output and cost are left out.
+func Render(d Delta, r ports.Renderer) string {
+	if d.IsZero() {
+		return ""
+	}
+	sep := r.Dim(" · ")
+	text := r.Dim("Δ ") + signed(d.Context, r)
+	if d.Output > 0 {
+		text += sep + r.FormatTokensF(d.Output) + r.Dim(" out")
+	}
+	if d.Cost >= 0.005 {
+		text += sep + r.Style(r.FormatCost(d.Cost), types.RoleBurn)
+	}
+	return text
+}
+
+// signed formats a token change with its sign: "+12.4K", "-3.0K", "0".
+func signed(n int, r ports.Renderer) string {
+	switch {
+	case n > 0:
+		return "+" + r.FormatTokensF(n)
+	case n < 0:
+		return "-" + r.FormatTokensF(-n)
+	}
+	return "0"
+}
@@ -0,0 +1,101 @@
+package delta
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockCache implements ports.CacheStore for testing.
+type mockCache struct {
+	files map[string][]byte
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{files: make(map[string][]byte)}
+}
+
+func (m *mockCache) AtomicWrite(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) { return nil, false }
+func (m *mockCache) ReadFile(path string) ([]byte, error) {
+	data, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+func (m *mockCache) WriteFile(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+func (m *mockCache) FileMTime(path string) (time.Time, error) { return time.Now(), nil }
+func (m *mockCache) CleanOld(dir, pattern, keep string) error { return nil }
+
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string                     { return text }
+func (m *mockRenderer) Dim(text string) string                                        { return text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                { return "" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string { return "" }
+func (m *mockRenderer) FormatTokens(n int) string                                     { return types.Locale{}.Tokens(n, 0) }
+func (m *mockRenderer) FormatTokensF(n int) string                                    { return types.Locale{}.Tokens(n, 1) }
+func (m *mockRenderer) FormatCost(f float64) string                                   { return types.Locale{}.Cost(f) }
+func (m *mockRenderer) Icon(name types.Icon) string                                   { return "" }
+
+func TestTrack(t *testing.T) {
+	cfg := types.DefaultConfig()
+	store := newMockCache()
+
+	steps := []struct {
+		name  string
+		frame Frame
+		want  Delta
+	}{
+		{"first frame", Frame{Context: 20000, Output: 500, Cost: 0.10}, Delta{}},
+		{"turn", Frame{Context: 32400, Output: 1350, Cost: 0.18}, Delta{Context: 12400, Output: 850, Cost: 0.08}},
+		{"unchanged keeps last delta", Frame{Context: 32400, Output: 1350, Cost: 0.18}, Delta{Context: 12400, Output: 850, Cost: 0.08}},
+		{"compaction", Frame{Context: 8000, Output: 1600, Cost: 0.21}, Delta{Context: -24400, Output: 250, Cost: 0.03}},
+		{"cost reset", Frame{Context: 1000, Output: 10, Cost: 0.01}, Delta{}},
+	}
+	for _, s := range steps {
+		got := Track(s.frame, "s1", cfg, store)
+		if got.Context != s.want.Context || got.Output != s.want.Output || fmt.Sprintf("%.2f", got.Cost) != fmt.Sprintf("%.2f", s.want.Cost) {
+			t.Errorf("%s: Track() = %+v, want %+v", s.name, got, s.want)
+		}
+	}
+
+	// Sessions are tracked separately
+	if got := Track(Frame{Context: 50000, Cost: 1}, "s2", cfg, store); !got.IsZero() {
+		t.Errorf("first frame of another session = %+v, want zero", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := &mockRenderer{}
+	tests := []struct {
+		name string
+		d    Delta
+		want string
+	}{
+		{"no change", Delta{}, ""},
+		{"full", Delta{Context: 12400, Output: 850, Cost: 0.08}, "Δ +12.4K · 850 out · $0.08"},
+		{"compaction", Delta{Context: -3000, Output: 1200}, "Δ -3.0K · 1.2K out"},
+		{"cost only", Delta{Cost: 0.02}, "Δ 0 · $0.02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.d, r); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+const (
+	cacheTTL   = 5 * time.Second
+	gitTimeout = 300 * time.Millisecond
+	shortSHA   = 7
+)
+
+// Status holds the state of a git working tree.
+type Status struct {
+	Root     string `json:"root"`
+	Branch   string `json:"branch"` // "" when HEAD is detached
+	SHA      string `json:"sha"`    // Commit of HEAD ("" on an unborn branch)
+	Counted  bool   `json:"counted"`
+	Staged   int    `json:"staged"`   // Changes in the index (only when Counted)
+	Dirty    int    `json:"dirty"`    // Unstaged changes, conflicts and untracked files (only when Counted)
+	Upstream string `json:"upstream"` // e.g. "origin/main" ("" = none, or not Counted)
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+}
+
+// Detached reports whether HEAD points to a commit instead of a branch.
+func (s Status) Detached() bool {
+	return s.Branch == ""
+}
+
+// ShortSHA returns the abbreviated commit of HEAD.
+func (s Status) ShortSHA() string {
+	if len(s.SHA) > shortSHA {
+		return s.SHA[:shortSHA]
+	}
+	return s.SHA
+}
+
+// Load returns the status of the repository containing dir, or nil outside
+// a repository. Branch and commit are read from .git directly; change counts
+// and upstream tracking come from `git status`, cached per repository for a
+// few seconds. When git is missing or too slow, only branch and commit are set.
+func Load(dir string, cfg types.Config, store ports.CacheStore) *Status {
+	r, ok := findRepo(dir)
+	if !ok {
+		return nil
+	}
+	branch, sha, native := r.readHead()
+
+	cachePath := fmt.Sprintf("%s/claude_git_%s.json", cfg.CacheDir, repoKey(r.Root))
+	if data, fresh := store.ReadIfFresh(cachePath, cacheTTL); fresh && native {
+		var cached Status
+		// A checkout or commit since caching invalidates the counts
+		if json.Unmarshal(data, &cached) == nil && cached.Root == r.Root && cached.Branch == branch && cached.SHA == sha {
+			return &cached
+		}
+	}
+
+	st := Status{Root: r.Root, Branch: branch, SHA: sha}
+	if out, err := runStatus(r.Root); err == nil {
+		counted := parsePorcelain(out)
+		if !native {
+			// HEAD in a format we don't read (e.g. reftable): trust git
+			st.Branch, st.SHA = counted.Branch, counted.SHA
+		}
+		st.Counted = true
+		st.Staged, st.Dirty = counted.Staged, counted.Dirty
+		st.Upstream, st.Ahead, st.Behind = counted.Upstream, counted.Ahead, counted.Behind
+	} else if !native {
+		return nil
+	}
+
+	if raw, err := json.Marshal(st); err == nil {
+		store.AtomicWrite(cachePath, raw)
+	}
+	return &st
+}
+
+// repoKey names the cache file of a repository.
+func repoKey(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return hex.EncodeToString(sum[:6])
+}
+
+// runStatus runs `git status` in porcelain v2 format, giving up after gitTimeout.
+// Optional locks are skipped so the statusline never blocks the user's git.
+func runStatus(root string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, "git", "--no-optional-locks", "-C", root,
+		"status", "--porcelain=v2", "--branch", "--untracked-files=normal").Output()
+}
+
+// parsePorcelain reads `git status --porcelain=v2 --branch` output.
+func parsePorcelain(out []byte) Status {
+	var st Status
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if header, ok := strings.CutPrefix(line, "# "); ok {
+			key, value, _ := strings.Cut(header, " ")
+			switch key {
+			case "branch.oid":
+				if value != "(initial)" {
+					st.SHA = value
+				}
+			case "branch.head":
+				if value != "(detached)" {
+					st.Branch = value
+				}
+			case "branch.upstream":
+				st.Upstream = value
+			case "branch.ab":
+				if ahead, behind, ok := strings.Cut(value, " "); ok {
+					st.Ahead, _ = strconv.Atoi(strings.TrimPrefix(ahead, "+"))
+					st.Behind, _ = strconv.Atoi(strings.TrimPrefix(behind, "-"))
+				}
+			}
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		switch fields[0] {
+		case "1", "2": // ordinary and renamed/copied entries: "1 XY ..."
+			if len(fields) < 2 || len(fields[1]) != 2 {
+				continue
+			}
+			if fields[1][0] != '.' {
+				st.Staged++
+			}
+			if fields[1][1] != '.' {
+				st.Dirty++
+			}
+		case "u", "?": // unmerged, untracked
+			st.Dirty++
+		}
+	}
+	return st
+}
+
+// Render produces the git section: branch (or "@" and the short commit when
+// detached), staged "+N", unstaged "~N" and "↑N↓N" versus the upstream.
+func Render(st *Status, r ports.Renderer) string {
+	if st == nil {
+		return ""
+	}
+	text := st.Branch
+	if st.Detached() {
+		if st.SHA == "" {
+			return ""
+		}
+		text = r.Dim("@") + st.ShortSHA()
+	}
+	if st.Staged > 0 {
+		text += " " + r.Style(fmt.Sprintf("+%d", st.Staged), types.RoleOK)
+	}
+	if st.Dirty > 0 {
+		text += " " + r.Style(fmt.Sprintf("~%d", st.Dirty), types.RoleWarn)
+	}
+	var sync string
+	if st.Ahead > 0 {
+		sync += fmt.Sprintf("↑%d", st.Ahead)
+	}
+	if st.Behind > 0 {
+		sync += fmt.Sprintf("↓%d", st.Behind)
+	}
+	if sync != "" {
+		text += " " + r.Dim(sync)
+	}
+	return text
+}
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockCache implements ports.CacheStore for testing; every file is fresh.
+type mockCache struct {
+	files map[string][]byte
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{files: make(map[string][]byte)}
+}
+
+func (m *mockCache) AtomicWrite(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) {
+	data, ok := m.files[path]
+	return data, ok
+}
+func (m *mockCache) ReadFile(path string) ([]byte, error) {
+	data, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+func (m *mockCache) WriteFile(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+func (m *mockCache) FileMTime(path string) (time.Time, error) { return time.Now(), nil }
+func (m *mockCache) CleanOld(dir, pattern, keep string) error { return nil }
+
+// mockRenderer marks dimmed and styled text: "<warn>~2".
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string {
+	return "<" + string(role) + ">" + text
+}
+func (m *mockRenderer) Dim(text string) string                                        { return "<dim>" + text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                { return "" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string { return "" }
+func (m *mockRenderer) FormatTokens(n int) string                                     { return "" }
+func (m *mockRenderer) FormatTokensF(n int) string                                    { return "" }
+func (m *mockRenderer) FormatCost(f float64) string                                   { return "" }
+func (m *mockRenderer) Icon(name types.Icon) string                                   { return "" }
+
+func TestParsePorcelain(t *testing.T) {
+	out := `# branch.oid ` + shaA + `
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +2 -1
+1 M. N... 100644 100644 100644 aaa bbb staged.go
+1 .M N... 100644 100644 100644 aaa bbb unstaged.go
+1 MM N... 100644 100644 100644 aaa bbb both.go
+2 R. N... 100644 100644 100644 aaa bbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go
+? untracked.go
+! ignored.log
+`
+	got := parsePorcelain([]byte(out))
+	want := Status{SHA: shaA, Branch: "main", Upstream: "origin/main", Ahead: 2, Behind: 1, Staged: 3, Dirty: 4}
+	if got != want {
+		t.Errorf("parsePorcelain() = %+v, want %+v", got, want)
+	}
+
+	detached := parsePorcelain([]byte("# branch.oid (initial)\n# branch.head (detached)\n"))
+	if detached != (Status{}) {
+		t.Errorf("initial detached = %+v, want zero", detached)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := &mockRenderer{}
+	tests := []struct {
+		name string
+		st   *Status
+		want string
+	}{
+		{"outside repository", nil, ""},
+		{"clean branch", &Status{Branch: "main", SHA: shaA}, "main"},
+		{"detached", &Status{SHA: shaA}, "<dim>@1111111"},
+		{"unborn detached", &Status{}, ""},
+		{"changes", &Status{Branch: "main", Staged: 1, Dirty: 2}, "main <ok>+1 <warn>~2"},
+		{"ahead and behind", &Status{Branch: "main", Ahead: 3, Behind: 1}, "main <dim>↑3↓1"},
+		{"behind only", &Status{Branch: "dev", Dirty: 1, Behind: 4}, "dev <warn>~1 <dim>↓4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.st, r); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	gitCmd("init", "-q", "-b", "main")
+	gitCmd("config", "user.name", "Test")
+	gitCmd("config", "user.email", "test@example.com")
+	writeFiles(t, root, map[string]string{"a.txt": "a", "b.txt": "b"})
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "initial")
+
+	writeFiles(t, root, map[string]string{"a.txt": "changed", "c.txt": "new", "sub/d.txt": "d"})
+	gitCmd("add", "c.txt")
+
+	cfg := types.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	store := newMockCache()
+
+	st := Load(filepath.Join(root, "sub"), cfg, store)
+	if st == nil {
+		t.Fatal("Load() = nil inside a repository")
+	}
+	if st.Branch != "main" || len(st.SHA) != 40 || !st.Counted {
+		t.Errorf("Load() = %+v, want counted main with a commit", st)
+	}
+	if st.Staged != 1 || st.Dirty != 2 { // c.txt staged; a.txt modified, sub/ untracked
+		t.Errorf("Staged, Dirty = %d, %d, want 1, 2", st.Staged, st.Dirty)
+	}
+
+	// Cached within the TTL while HEAD is unchanged
+	writeFiles(t, root, map[string]string{"b.txt": "changed"})
+	if cached := Load(root, cfg, store); cached.Dirty != 2 {
+		t.Errorf("cached Dirty = %d, want 2", cached.Dirty)
+	}
+
+	// A commit moves HEAD and invalidates the cache
+	gitCmd("commit", "-q", "-m", "add c")
+	after := Load(root, cfg, store)
+	if after.SHA == st.SHA || after.Staged != 0 || after.Dirty != 3 {
+		t.Errorf("after commit = %+v, want new SHA, 0 staged, 3 dirty", after)
+	}
+
+	if got := Load(t.TempDir(), cfg, store); got != nil && got.Root == root {
+		t.Errorf("Load() outside the repository = %+v", got)
+	}
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// repo locates a working tree and its git directories.
+type repo struct {
+	Root      string // Working tree root
+	GitDir    string // .git directory (per worktree: HEAD lives here)
+	CommonDir string // Shared directory with refs and packed-refs (= GitDir outside linked worktrees)
+}
+
+// findRepo walks up from dir to the nearest directory containing .git.
+// A .git file ("gitdir: <path>", used by worktrees and submodules) is followed.
+func findRepo(dir string) (repo, bool) {
+	if dir == "" {
+		return repo{}, false
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return repo{}, false
+	}
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir = readGitFile(dotGit, dir); gitDir == "" {
+					return repo{}, false
+				}
+			}
+			return repo{Root: dir, GitDir: gitDir, CommonDir: commonDir(gitDir)}, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return repo{}, false
+		}
+		dir = parent
+	}
+}
+
+// readGitFile returns the directory a .git file points to, relative paths
+// being relative to the working tree root.
+func readGitFile(path, root string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	return target
+}
+
+// commonDir returns the directory named by gitDir/commondir, or gitDir itself.
+func commonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	dir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return dir
+}
+
+// readHead returns the checked-out branch ("" when detached) and the commit
+// HEAD points to ("" on an unborn branch or when the ref cannot be resolved).
+func (r repo) readHead() (branch, sha string, ok bool) {
+	data, err := os.ReadFile(filepath.Join(r.GitDir, "HEAD"))
+	if err != nil {
+		return "", "", false
+	}
+	head := strings.TrimSpace(string(data))
+	ref, symbolic := strings.CutPrefix(head, "ref:")
+	if !symbolic {
+		return "", head, isSHA(head)
+	}
+	ref = strings.TrimSpace(ref)
+	return strings.TrimPrefix(ref, "refs/heads/"), r.resolve(ref), true
+}
+
+// resolve returns the commit of a ref from its loose file or packed-refs.
+func (r repo) resolve(ref string) string {
+	for _, dir := range []string{r.GitDir, r.CommonDir} {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			if sha := strings.TrimSpace(string(data)); isSHA(sha) {
+				return sha
+			}
+		}
+	}
+	return r.packedRef(ref)
+}
+
+// packedRef looks ref up in packed-refs ("<sha> <ref>" lines).
+func (r repo) packedRef(ref string) string {
+	f, err := os.Open(filepath.Join(r.CommonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sha, name, found := strings.Cut(scanner.Text(), " ")
+		if found && name == ref && isSHA(sha) {
+			return sha
+		}
+	}
+	return ""
+}
+
+// isSHA reports whether s is a full hex object name (SHA-1 or SHA-256).
+func isSHA(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
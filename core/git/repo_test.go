@@ -0,0 +1,115 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	shaA = "1111111111111111111111111111111111111111"
+	shaB = "2222222222222222222222222222222222222222"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadHead(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantBranch string
+		wantSHA    string
+	}{
+		{
+			name: "loose ref",
+			files: map[string]string{
+				".git/HEAD":            "ref: refs/heads/main\n",
+				".git/refs/heads/main": shaA + "\n",
+			},
+			wantBranch: "main",
+			wantSHA:    shaA,
+		},
+		{
+			name: "packed ref",
+			files: map[string]string{
+				".git/HEAD":        "ref: refs/heads/feature/x\n",
+				".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + shaB + " refs/heads/feature/x\n^" + shaA + "\n",
+			},
+			wantBranch: "feature/x",
+			wantSHA:    shaB,
+		},
+		{
+			name: "loose ref wins over packed",
+			files: map[string]string{
+				".git/HEAD":            "ref: refs/heads/main\n",
+				".git/refs/heads/main": shaA + "\n",
+				".git/packed-refs":     shaB + " refs/heads/main\n",
+			},
+			wantBranch: "main",
+			wantSHA:    shaA,
+		},
+		{
+			name:    "detached",
+			files:   map[string]string{".git/HEAD": shaA + "\n"},
+			wantSHA: shaA,
+		},
+		{
+			name:       "unborn branch",
+			files:      map[string]string{".git/HEAD": "ref: refs/heads/main\n"},
+			wantBranch: "main",
+		},
+		{
+			name: "linked worktree",
+			files: map[string]string{
+				"main/.git/refs/heads/topic":       shaB + "\n",
+				"main/.git/worktrees/wt/HEAD":      "ref: refs/heads/topic\n",
+				"main/.git/worktrees/wt/commondir": "../..\n",
+				".git":                             "gitdir: main/.git/worktrees/wt\n",
+			},
+			wantBranch: "topic",
+			wantSHA:    shaB,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.files)
+			os.MkdirAll(filepath.Join(root, "sub", "dir"), 0o755)
+
+			r, ok := findRepo(filepath.Join(root, "sub", "dir"))
+			if !ok {
+				t.Fatal("repository not found from subdirectory")
+			}
+			if r.Root != root {
+				t.Errorf("Root = %q, want %q", r.Root, root)
+			}
+			branch, sha, ok := r.readHead()
+			if !ok || branch != tt.wantBranch || sha != tt.wantSHA {
+				t.Errorf("readHead() = %q, %q, %v, want %q, %q", branch, sha, ok, tt.wantBranch, tt.wantSHA)
+			}
+		})
+	}
+}
+
+func TestFindRepoOutside(t *testing.T) {
+	dir := t.TempDir()
+	if r, ok := findRepo(dir); ok && strings.HasPrefix(r.Root, dir) {
+		t.Errorf("found repository %q in an empty directory", r.Root)
+	}
+	if _, ok := findRepo(""); ok {
+		t.Error("empty directory should not resolve to a repository")
+	}
+}
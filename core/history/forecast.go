@@ -0,0 +1,159 @@
+package history
+
+import (
+	"math"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+const (
+	forecastSpan = 24 * time.Hour   // Work time fitted
+	maxGap       = 10 * time.Minute // Longer pauses between points count as this much work time
+	halfLife     = 6 * time.Hour    // Weight of a point halves every 6h of work time back
+	minPoints    = 10
+	resetDrop    = 5.0 // A drop in usage of more points than this is a window reset
+)
+
+// Confidence rates how well recent usage predicts the future.
+type Confidence int
+
+const (
+	ConfidenceNone Confidence = iota // Not enough history
+	ConfidenceLow
+	ConfidenceMedium
+	ConfidenceHigh
+)
+
+// String names the confidence: "none", "low", "medium" or "high".
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	}
+	return "none"
+}
+
+// Forecast projects the weekly (7d) usage from its recent slope.
+type Forecast struct {
+	Rate       float64   // Percent per hour of work time
+	ExhaustAt  time.Time // When 100% is reached at the recent rate (zero = not before the reset)
+	Confidence Confidence
+}
+
+// SevenDayForecast projects when the weekly limit runs out.
+//
+// Points are placed on a work-time axis: the statusline records only while
+// Claude Code runs, so the time between close points is work time, and a pause
+// counts as at most maxGap. A weighted linear regression over the last 24h of
+// work time, recent points weighing most, gives the rate per work hour. It is
+// projected onto wall-clock time with the share of work time in the days
+// fitted. Confidence combines the fit (R²) with how much of the 24h is covered.
+func SevenDayForecast(points []Point, data types.RateLimitData, now time.Time) Forecast {
+	if data.SevenDayReset.IsZero() {
+		return Forecast{}
+	}
+	windowStart := data.SevenDayReset.Add(-sevenDaySpan)
+
+	// Walk back from the newest point to collect the fitted span
+	var xs, ys []float64
+	var work time.Duration
+	newest, oldest := -1, -1
+	for i := len(points) - 1; i >= 0; i-- {
+		p := points[i]
+		if p.Time.After(now) {
+			continue
+		}
+		if p.Time.Before(windowStart) {
+			break
+		}
+		if oldest < 0 {
+			newest = i
+		} else {
+			next := points[oldest]
+			if p.SevenDay > next.SevenDay+resetDrop {
+				break // the previous window
+			}
+			step := min(next.Time.Sub(p.Time), maxGap)
+			if work+step > forecastSpan {
+				break
+			}
+			work += step
+		}
+		xs = append(xs, -work.Hours())
+		ys = append(ys, p.SevenDay)
+		oldest = i
+	}
+	if len(xs) < minPoints || work <= 0 {
+		return Forecast{}
+	}
+
+	rate, r2 := regression(xs, ys)
+	fc := Forecast{Rate: rate, Confidence: confidence(r2, work)}
+	if rate <= 0 {
+		return fc
+	}
+
+	// Work hours per wall-clock hour, over the whole days the fitted span
+	// touches so that nights and days off count
+	wall := points[newest].Time.Sub(points[oldest].Time)
+	days := math.Max(1, math.Ceil(wall.Hours()/24))
+	share := math.Min(1, work.Hours()/(days*24))
+	remaining := 100 - data.SevenDayPercent
+	if remaining <= 0 {
+		fc.ExhaustAt = now
+		return fc
+	}
+	at := now.Add(time.Duration(remaining / (rate * share) * float64(time.Hour)))
+	if at.Before(data.SevenDayReset) {
+		fc.ExhaustAt = at
+	}
+	return fc
+}
+
+// regression fits y = a + b·x, weighting points by their age in work time
+// (x ≤ 0, in hours), and returns the slope b and the weighted R².
+func regression(xs, ys []float64) (slope, r2 float64) {
+	var sw, sx, sy float64
+	ws := make([]float64, len(xs))
+	for i, x := range xs {
+		ws[i] = math.Exp2(x / halfLife.Hours())
+		sw += ws[i]
+		sx += ws[i] * x
+		sy += ws[i] * ys[i]
+	}
+	mx, my := sx/sw, sy/sw
+
+	var sxx, sxy, syy float64
+	for i, x := range xs {
+		dx, dy := x-mx, ys[i]-my
+		sxx += ws[i] * dx * dx
+		sxy += ws[i] * dx * dy
+		syy += ws[i] * dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0
+	}
+	slope = sxy / sxx
+	if syy == 0 {
+		return slope, 1 // flat usage, fitted exactly
+	}
+	return slope, sxy * sxy / (sxx * syy)
+}
+
+// confidence rates a fit by its R² and the work time it covers.
+func confidence(r2 float64, work time.Duration) Confidence {
+	coverage := work.Hours() / forecastSpan.Hours()
+	switch {
+	case r2 >= 0.8 && coverage >= 0.5:
+		return ConfidenceHigh
+	case r2 >= 0.5 && coverage >= 0.25:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
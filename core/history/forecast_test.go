@@ -0,0 +1,82 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// workdays records a point every 5 minutes from 9:00 to 17:00 on each day,
+// with the 7d usage growing by perHour during work.
+func workdays(from time.Time, days int, perHour float64) []Point {
+	var points []Point
+	used := 0.0
+	for d := 0; d < days; d++ {
+		day := from.AddDate(0, 0, d)
+		for m := 0; m < 8*60; m += 5 {
+			points = append(points, Point{Time: day.Add(9*time.Hour + time.Duration(m)*time.Minute), SevenDay: used})
+			used += perHour / 12
+		}
+	}
+	return points
+}
+
+func TestSevenDayForecast(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	points := workdays(monday, 3, 2.5) // 20% per work day
+	last := points[len(points)-1]
+	now := last.Time.Add(time.Minute) // Wednesday 17:00
+	data := types.RateLimitData{SevenDayPercent: last.SevenDay, SevenDayReset: monday.AddDate(0, 0, 7)}
+
+	fc := SevenDayForecast(points, data, now)
+	if fc.Confidence != ConfidenceHigh {
+		t.Errorf("Confidence = %v, want high", fc.Confidence)
+	}
+	if fc.Rate < 2.4 || fc.Rate > 2.6 {
+		t.Errorf("Rate = %.2f%%/h, want ≈2.5", fc.Rate)
+	}
+	// 40% left at 2.5%/h of work, 8 of 24 hours worked: 48h from now
+	if want := now.Add(48 * time.Hour); fc.ExhaustAt.Sub(want).Abs() > 2*time.Hour {
+		t.Errorf("ExhaustAt = %v, want ≈%v", fc.ExhaustAt, want)
+	}
+
+	// At half the rate the limit lasts until the reset
+	slow := workdays(monday, 3, 1.25)
+	data.SevenDayPercent = slow[len(slow)-1].SevenDay
+	fc = SevenDayForecast(slow, data, now)
+	if !fc.ExhaustAt.IsZero() || fc.Confidence != ConfidenceHigh {
+		t.Errorf("slow usage: ExhaustAt = %v, Confidence = %v; want none, high", fc.ExhaustAt, fc.Confidence)
+	}
+}
+
+func TestSevenDayForecastLimits(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	reset := monday.AddDate(0, 0, 7)
+
+	// Too little history
+	few := workdays(monday, 1, 5)[:5]
+	if fc := SevenDayForecast(few, types.RateLimitData{SevenDayReset: reset}, few[4].Time); fc.Confidence != ConfidenceNone {
+		t.Errorf("5 points: Confidence = %v, want none", fc.Confidence)
+	}
+
+	// An hour of history gives a low-confidence forecast
+	hour := workdays(monday, 1, 30)[:12]
+	now := hour[11].Time
+	fc := SevenDayForecast(hour, types.RateLimitData{SevenDayPercent: hour[11].SevenDay, SevenDayReset: reset}, now)
+	if fc.Confidence != ConfidenceLow || fc.ExhaustAt.IsZero() {
+		t.Errorf("1h of history: Confidence = %v, ExhaustAt = %v; want low with a projection", fc.Confidence, fc.ExhaustAt)
+	}
+
+	// Points of the previous window (before a drop) are not fitted
+	prev := workdays(monday.AddDate(0, 0, -7), 3, 5)
+	cur := workdays(monday, 1, 2.5)
+	fc = SevenDayForecast(append(prev, cur...), types.RateLimitData{SevenDayPercent: 20, SevenDayReset: reset}, cur[len(cur)-1].Time)
+	if fc.Rate < 2.4 || fc.Rate > 2.6 {
+		t.Errorf("after a reset: Rate = %.2f, want ≈2.5", fc.Rate)
+	}
+
+	if fc := SevenDayForecast(cur, types.RateLimitData{}, now); fc.Confidence != ConfidenceNone {
+		t.Error("no forecast without a reset time")
+	}
+}
@@ -0,0 +1,215 @@
+// Package history keeps a time series of the 5h and 7d rate limit usage in the
+// cache dir, shared by all sessions of an account, and draws it as sparklines.
+package history
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+const (
+	fileName  = "claude_rate_history.txt"
+	Interval  = time.Minute        // At most one point per interval
+	Retention = 8 * 24 * time.Hour // Points older than this are dropped
+	pruneLag  = 24 * time.Hour     // Rewrite the file once its oldest point is this far past retention
+
+	fiveHourWindow = 5 * time.Hour
+	fiveHourBucket = 30 * time.Minute // 10 bars per 5h window
+	sevenDaySpan   = 7 * 24 * time.Hour
+	sevenDayBucket = 12 * time.Hour // 14 bars for the last 7 days
+)
+
+// Point is one sample of the rate limit usage.
+type Point struct {
+	Time     time.Time
+	FiveHour float64 // Percent used
+	SevenDay float64 // Percent used
+}
+
+// Record adds the current usage to the history, once per Interval, and
+// returns the points of the last Retention like Load.
+//
+// The history is a text file with one point per line, shared by all tabs of
+// the account with the given key (see types.Credentials.AccountKey).
+// Points are appended with a single write, so concurrent renders never
+// corrupt it; two tabs recording in the same minute both append, and Load
+// keeps the last. Once a day the expired points are dropped by rewriting the
+// file atomically; a point appended during the rewrite may be lost.
+func Record(data types.RateLimitData, account string, cfg types.Config, store ports.CacheStore, now time.Time) []Point {
+	path := filePath(account, cfg)
+	raw, _ := store.ReadFile(path)
+	points := parse(raw)
+	if n := len(points); n > 0 && points[n-1].Time.Truncate(Interval).Equal(now.Truncate(Interval)) {
+		return trim(points, now)
+	}
+
+	p := Point{Time: now, FiveHour: data.FiveHourPercent, SevenDay: data.SevenDayPercent}
+	expired := len(points) > 0 && now.Sub(points[0].Time) > Retention+pruneLag
+	points = trim(append(points, p), now)
+	if expired {
+		var b strings.Builder
+		for _, kept := range points {
+			b.WriteString(format(kept))
+		}
+		store.AtomicWrite(path, []byte(b.String()))
+	} else {
+		store.AppendFile(path, []byte(format(p)))
+	}
+	return points
+}
+
+// Load returns the account's points of the last Retention, oldest first, one
+// per Interval.
+func Load(account string, cfg types.Config, store ports.CacheStore, now time.Time) []Point {
+	raw, err := store.ReadFile(filePath(account, cfg))
+	if err != nil {
+		return nil
+	}
+	return trim(parse(raw), now)
+}
+
+func filePath(account string, cfg types.Config) string {
+	return fmt.Sprintf("%s/%s", cfg.CacheDir, types.AccountFile(fileName, account))
+}
+
+// format encodes a point as "<unix seconds> <5h%> <7d%>\n".
+func format(p Point) string {
+	return fmt.Sprintf("%d %.1f %.1f\n", p.Time.Unix(), p.FiveHour, p.SevenDay)
+}
+
+// parse decodes the history file, skipping malformed lines (e.g. a partial
+// write), sorted by time with one point per Interval (the last written).
+func parse(raw []byte) []Point {
+	var points []Point
+	for _, line := range strings.Split(string(raw), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		sec, err1 := strconv.ParseInt(fields[0], 10, 64)
+		five, err2 := strconv.ParseFloat(fields[1], 64)
+		seven, err3 := strconv.ParseFloat(fields[2], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		points = append(points, Point{Time: time.Unix(sec, 0), FiveHour: five, SevenDay: seven})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	deduped := points[:0]
+	for _, p := range points {
+		if n := len(deduped); n > 0 && deduped[n-1].Time.Truncate(Interval).Equal(p.Time.Truncate(Interval)) {
+			deduped[n-1] = p
+			continue
+		}
+		deduped = append(deduped, p)
+	}
+	return deduped
+}
+
+// trim drops the points older than Retention.
+func trim(points []Point, now time.Time) []Point {
+	cutoff := now.Add(-Retention)
+	i := sort.Search(len(points), func(i int) bool { return !points[i].Time.Before(cutoff) })
+	return points[i:]
+}
+
+// Sparks are the sparkline levels, lowest first.
+var Sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Spark draws one bar per value (percent, 0-100). A negative value is a gap
+// without data and is drawn as a space.
+func Spark(values []float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		if v < 0 {
+			b.WriteByte(' ')
+			continue
+		}
+		level := int(math.Round(math.Min(v, 100) / 100 * float64(len(Sparks)-1)))
+		b.WriteRune(Sparks[level])
+	}
+	return b.String()
+}
+
+// buckets splits [start, end] into intervals of size and returns the highest
+// 5h or 7d percent seen in each. Buckets without points repeat the previous
+// bucket; those before the first point are gaps (-1).
+func buckets(points []Point, start, end time.Time, size time.Duration, value func(Point) float64) []float64 {
+	if !end.After(start) {
+		return nil
+	}
+	n := int((end.Sub(start) + size - 1) / size)
+	values := make([]float64, n)
+	seen := make([]bool, n)
+	for _, p := range points {
+		if p.Time.Before(start) || p.Time.After(end) {
+			continue
+		}
+		i := min(int(p.Time.Sub(start)/size), n-1)
+		if !seen[i] || value(p) > values[i] {
+			values[i], seen[i] = value(p), true
+		}
+	}
+	prev := -1.0
+	for i := range values {
+		if seen[i] {
+			prev = values[i]
+		} else {
+			values[i] = prev
+		}
+	}
+	return values
+}
+
+// FiveHour returns the sparkline of the current 5h window, one bar per
+// 30 minutes up to now, or "" without points in it.
+func FiveHour(points []Point, reset, now time.Time) string {
+	if reset.IsZero() {
+		return ""
+	}
+	start, end := reset.Add(-fiveHourWindow), now
+	if end.After(reset) {
+		end = reset
+	}
+	return spark(buckets(points, start, end, fiveHourBucket, func(p Point) float64 { return p.FiveHour }))
+}
+
+// SevenDay returns the sparkline of the last 7 days, one bar per 12 hours,
+// or "" without points in them.
+func SevenDay(points []Point, now time.Time) string {
+	start := now.Add(-sevenDaySpan)
+	return spark(buckets(points, start, now, sevenDayBucket, func(p Point) float64 { return p.SevenDay }))
+}
+
+// spark draws values from the first one with data (before any history was
+// recorded), or "" when all are gaps.
+func spark(values []float64) string {
+	for i, v := range values {
+		if v >= 0 {
+			return Spark(values[i:])
+		}
+	}
+	return ""
+}
+
+// Render produces the history section: "5h ▁▂▃▅ 7d ▂▃▃▅▇", each sparkline
+// colored by its current usage and the 5h/7d thresholds. Sparklines without
+// data are left out.
+func Render(points []Point, data types.RateLimitData, cfg types.Config, now time.Time, r ports.Renderer) string {
+	var parts []string
+	if s := FiveHour(points, data.FiveHourReset, now); s != "" {
+		parts = append(parts, r.Dim("5h ")+r.Style(s, cfg.Thresholds.FiveHour.Role(data.FiveHourPercent)))
+	}
+	if s := SevenDay(points, now); s != "" {
+		parts = append(parts, r.Dim("7d ")+r.Style(s, cfg.Thresholds.SevenDay.Role(data.SevenDayPercent)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,195 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockCache implements ports.CacheStore for testing.
+type mockCache struct {
+	files    map[string][]byte
+	appends  int
+	rewrites int
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{files: make(map[string][]byte)}
+}
+
+func (m *mockCache) AtomicWrite(path string, data []byte) error {
+	m.files[path] = data
+	m.rewrites++
+	return nil
+}
+func (m *mockCache) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) { return nil, false }
+func (m *mockCache) ReadFile(path string) ([]byte, error) {
+	data, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+func (m *mockCache) WriteFile(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	m.appends++
+	return nil
+}
+func (m *mockCache) FileMTime(path string) (time.Time, error) { return time.Now(), nil }
+func (m *mockCache) CleanOld(dir, pattern, keep string) error { return nil }
+
+// mockRenderer implements ports.Renderer for testing.
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string                     { return text }
+func (m *mockRenderer) Dim(text string) string                                        { return text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                { return "" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string { return "" }
+func (m *mockRenderer) FormatTokens(n int) string                                     { return "" }
+func (m *mockRenderer) FormatTokensF(n int) string                                    { return "" }
+func (m *mockRenderer) FormatCost(f float64) string                                   { return "" }
+func (m *mockRenderer) Icon(name types.Icon) string                                   { return "" }
+
+var start = time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+
+func testConfig() types.Config {
+	cfg := types.DefaultConfig()
+	cfg.CacheDir = "/cache"
+	return cfg
+}
+
+func TestRecordOncePerInterval(t *testing.T) {
+	store := newMockCache()
+	cfg := testConfig()
+
+	Record(types.RateLimitData{FiveHourPercent: 10, SevenDayPercent: 5}, "", cfg, store, start)
+	Record(types.RateLimitData{FiveHourPercent: 11, SevenDayPercent: 5}, "", cfg, store, start.Add(30*time.Second))
+	Record(types.RateLimitData{FiveHourPercent: 12, SevenDayPercent: 6}, "", cfg, store, start.Add(time.Minute))
+
+	want := fmt.Sprintf("%d 10.0 5.0\n%d 12.0 6.0\n", start.Unix(), start.Add(time.Minute).Unix())
+	if got := string(store.files["/cache/"+fileName]); got != want {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+	if store.appends != 2 || store.rewrites != 0 {
+		t.Errorf("appends = %d, rewrites = %d; want 2, 0", store.appends, store.rewrites)
+	}
+}
+
+func TestRecordPrunes(t *testing.T) {
+	store := newMockCache()
+	cfg := testConfig()
+	path := "/cache/" + fileName
+	old := start.Add(-Retention - pruneLag - time.Hour)
+	store.files[path] = []byte(fmt.Sprintf("%d 50.0 40.0\n%d 20.0 30.0\n", old.Unix(), start.Add(-time.Hour).Unix()))
+
+	Record(types.RateLimitData{FiveHourPercent: 25, SevenDayPercent: 31}, "", cfg, store, start)
+
+	want := fmt.Sprintf("%d 20.0 30.0\n%d 25.0 31.0\n", start.Add(-time.Hour).Unix(), start.Unix())
+	if got := string(store.files[path]); got != want {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+	if store.rewrites != 1 {
+		t.Errorf("rewrites = %d, want 1", store.rewrites)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	store := newMockCache()
+	cfg := testConfig()
+	expired := start.Add(-Retention - time.Minute)
+	// Out of order, a duplicate minute from a concurrent tab, and a partial write
+	store.files["/cache/"+fileName] = []byte(fmt.Sprintf("%d 1.0 1.0\n%d 3.0 3.0\n%d 2.0 2.0\n%d 2.5 2.0\n%d 4.0",
+		expired.Unix(), start.Add(time.Minute).Unix(), start.Unix(), start.Add(10*time.Second).Unix(), start.Add(2*time.Minute).Unix()))
+
+	points := Load("", cfg, store, start.Add(2*time.Minute))
+	if len(points) != 2 {
+		t.Fatalf("Load() = %+v, want 2 points", points)
+	}
+	if points[0].FiveHour != 2.5 || points[1].FiveHour != 3 {
+		t.Errorf("Load() = %+v, want 2.5 then 3", points)
+	}
+
+	if got := Load("", cfg, newMockCache(), start); got != nil {
+		t.Errorf("Load() without history = %+v", got)
+	}
+}
+
+func TestSpark(t *testing.T) {
+	if got := Spark([]float64{0, 14, 50, 100, 120, -1}); got != "▁▂▅██ " {
+		t.Errorf("Spark() = %q", got)
+	}
+}
+
+func TestFiveHour(t *testing.T) {
+	reset := start.Add(5 * time.Hour)
+	points := []Point{
+		{Time: start.Add(-time.Minute), FiveHour: 90}, // previous window
+		{Time: start.Add(40 * time.Minute), FiveHour: 10},
+		{Time: start.Add(50 * time.Minute), FiveHour: 20},
+		{Time: start.Add(2 * time.Hour), FiveHour: 60},
+	}
+	// Half hours since the window start: no data yet (left out), 20%, held twice, 60%
+	now := start.Add(2*time.Hour + 10*time.Minute)
+	if got := FiveHour(points, reset, now); got != "▂▂▂▅" {
+		t.Errorf("FiveHour() = %q, want %q", got, "▂▂▂▅")
+	}
+	if got := FiveHour(points[:1], reset, now); got != "" {
+		t.Errorf("FiveHour() without points in the window = %q, want empty", got)
+	}
+	if got := FiveHour(points, time.Time{}, now); got != "" {
+		t.Errorf("FiveHour() without reset = %q, want empty", got)
+	}
+}
+
+func TestSevenDay(t *testing.T) {
+	now := start
+	var points []Point
+	for day := 7; day >= 1; day-- {
+		points = append(points, Point{Time: now.Add(-time.Duration(day) * 24 * time.Hour).Add(time.Hour), SevenDay: float64(100 - day*10)})
+	}
+	got := SevenDay(points, now)
+	if n := len([]rune(got)); n != 14 { // a point in every 24h, held for the following 12h
+		t.Fatalf("SevenDay() = %q, want 14 bars", got)
+	}
+	if !strings.HasPrefix(got, "▃▃▄▄") || !strings.HasSuffix(got, "▇▇") {
+		t.Errorf("SevenDay() = %q", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := start.Add(35 * time.Minute)
+	points := []Point{{Time: start.Add(5 * time.Minute), FiveHour: 40, SevenDay: 30}}
+	data := types.RateLimitData{FiveHourPercent: 40, FiveHourReset: start.Add(5 * time.Hour), SevenDayPercent: 30}
+
+	cfg := types.DefaultConfig()
+
+	got := Render(points, data, cfg, now, &mockRenderer{})
+	if want := "5h ▄▄ 7d ▃"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+	if got := Render(nil, data, cfg, now, &mockRenderer{}); got != "" {
+		t.Errorf("Render() without history = %q, want empty", got)
+	}
+
+	// Colors follow the configured thresholds, like the 5h and 7d segments
+	cfg.Thresholds.FiveHour = types.Threshold{Warn: 30, Crit: 35}
+	cfg.Thresholds.SevenDay = types.Threshold{Warn: 20, Crit: 60}
+	got = Render(points, data, cfg, now, &roleRenderer{})
+	if want := "5h <critical>▄▄ 7d <warn>▃"; got != want {
+		t.Errorf("Render() with thresholds = %q, want %q", got, want)
+	}
+}
+
+// roleRenderer prefixes styled text with its role.
+type roleRenderer struct{ mockRenderer }
+
+func (r *roleRenderer) Style(text string, role types.Role) string {
+	return "<" + string(role) + ">" + text
+}
@@ -0,0 +1,248 @@
+package layout
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Glue controls how a section attaches to the section before it.
+type Glue int
+
+const (
+	GlueSeparator Glue = iota // "  │  " between major sections
+	GlueCompact               // " │ " (lines after duration)
+	GlueSpace                 // " " (update notice)
+)
+
+// Segment names understood by the layout.
+const (
+	Model     = "model"
+	Context   = "context"
+	FiveHour  = "5h"
+	Burn      = "burn"
+	SevenDay  = "7d"
+	Session   = "session"
+	Daily     = "daily"
+	Duration  = "duration"
+	Lines     = "lines"
+	Ollama    = "ollama"
+	Update    = "update"
+	Workspace = "workspace"
+	Version   = "version"
+	Git       = "git"
+	Delta     = "delta"
+	History   = "history"
+	Extra     = "extra"
+	Account   = "account"
+)
+
+// DefaultOAuth is the segment order for subscription (OAuth) accounts.
+var DefaultOAuth = []string{Model, Context, FiveHour, Burn, SevenDay, Duration, Lines, Ollama, Update}
+
+// DefaultAPIKey is the segment order for API-key accounts.
+var DefaultAPIKey = []string{Model, Context, Session, Daily, Burn, Duration, Lines, Ollama, Update}
+
+// Standalone returns the segments that need no Claude Code input, for running
+// outside a session (e.g. from a status bar): the account-wide rate limits.
+func Standalone(oauth bool) []string {
+	if oauth {
+		return []string{FiveHour, SevenDay}
+	}
+	return nil
+}
+
+// Section is a rendered, named piece of the statusline.
+type Section struct {
+	Name     string
+	Text     string
+	Compact  string // Shorter form used when the line is too wide ("" = none)
+	Glue     Glue
+	Priority int // Higher is kept longer when the line is too wide
+}
+
+// GlueFor returns how the named segment attaches to its predecessor.
+func GlueFor(name string) Glue {
+	switch name {
+	case Lines:
+		return GlueCompact
+	case Update:
+		return GlueSpace
+	default:
+		return GlueSeparator
+	}
+}
+
+// PriorityFor returns how important the named segment is when space runs out.
+// Lower priorities are compacted first, then dropped first.
+func PriorityFor(name string) int {
+	switch name {
+	case Model:
+		return 100
+	case Context:
+		return 90
+	case FiveHour, Session:
+		return 80
+	case Daily:
+		return 60
+	case Burn:
+		return 50
+	case Delta:
+		return 45
+	case SevenDay:
+		return 40
+	case Extra:
+		return 38
+	case History:
+		return 35
+	case Duration:
+		return 30
+	case Git:
+		return 28
+	case Workspace:
+		return 25
+	case Account:
+		return 22
+	case Lines:
+		return 20
+	case Ollama:
+		return 10
+	case Version:
+		return 5
+	case Update:
+		return 0
+	default:
+		return 50
+	}
+}
+
+// Order returns the configured segment names per output line,
+// or the single-line default for the account mode.
+func Order(cfg types.Config, oauth bool) [][]string {
+	if len(cfg.Segments) > 0 {
+		return cfg.Segments
+	}
+	if oauth {
+		return [][]string{DefaultOAuth}
+	}
+	return [][]string{DefaultAPIKey}
+}
+
+// Assemble joins sections in order, skipping empty ones.
+// The first visible section never gets a leading separator.
+// Block renderers get the line grouped into blocks instead (see Blocks).
+func Assemble(sections []Section, r ports.Renderer) string {
+	if br, ok := r.(ports.BlockRenderer); ok {
+		return br.JoinBlocks(Blocks(sections, r))
+	}
+
+	var b strings.Builder
+	for _, s := range sections {
+		if s.Text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(glueString(s.Glue, r))
+		}
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// Blocks groups visible sections into blocks: every section glued with a
+// separator starts a new block, compact and space glue stay in the current one.
+func Blocks(sections []Section, r ports.Renderer) []ports.Block {
+	var blocks []ports.Block
+	for _, s := range sections {
+		if s.Text == "" {
+			continue
+		}
+		if len(blocks) == 0 || s.Glue == GlueSeparator {
+			blocks = append(blocks, ports.Block{Name: s.Name, Text: s.Text})
+			continue
+		}
+		blocks[len(blocks)-1].Text += glueString(s.Glue, r) + s.Text
+	}
+	return blocks
+}
+
+// Fit shrinks a line until it fits within width visible columns.
+// Sections are first switched to their compact form, lowest priority first;
+// if that is not enough they are dropped in the same order. The most
+// important section is always kept. A width of 0 means unlimited.
+func Fit(sections []Section, r ports.Renderer, width int) []Section {
+	if width <= 0 || fits(sections, r, width) {
+		return sections
+	}
+
+	out := append([]Section(nil), sections...)
+	order := shrinkOrder(out)
+
+	for _, i := range order {
+		if out[i].Compact == "" || out[i].Compact == out[i].Text {
+			continue
+		}
+		out[i].Text = out[i].Compact
+		if fits(out, r, width) {
+			return out
+		}
+	}
+
+	for n, i := range order {
+		if n == len(order)-1 {
+			break
+		}
+		out[i].Text = ""
+		if fits(out, r, width) {
+			return out
+		}
+	}
+
+	return out
+}
+
+func fits(sections []Section, r ports.Renderer, width int) bool {
+	return RenderedWidth(Assemble(sections, r), r) <= width
+}
+
+// RenderedWidth returns the visible width of text produced by r, without the
+// renderer's style markup (see ports.MarkupRenderer).
+func RenderedWidth(s string, r ports.Renderer) int {
+	if mr, ok := r.(ports.MarkupRenderer); ok {
+		s = mr.StripMarkup(s)
+	}
+	return VisibleWidth(s)
+}
+
+// shrinkOrder returns the indices of visible sections, lowest priority first.
+// Ties go to the rightmost section so the line shrinks from the end.
+func shrinkOrder(sections []Section) []int {
+	var order []int
+	for i, s := range sections {
+		if s.Text != "" {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := sections[order[a]].Priority, sections[order[b]].Priority
+		if pa != pb {
+			return pa < pb
+		}
+		return order[a] > order[b]
+	})
+	return order
+}
+
+func glueString(g Glue, r ports.Renderer) string {
+	switch g {
+	case GlueCompact:
+		return " " + r.Dim("│") + " "
+	case GlueSpace:
+		return " "
+	default:
+		// 2 spaces + dim │ + 2 spaces (matching bash)
+		return "  " + r.Dim("│") + "  "
+	}
+}
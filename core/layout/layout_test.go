@@ -0,0 +1,289 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockRenderer implements ports.Renderer without escape codes.
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string      { return text }
+func (m *mockRenderer) Dim(text string) string                         { return text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
+	return "[split]"
+}
+func (m *mockRenderer) FormatTokens(n int) string   { return "" }
+func (m *mockRenderer) FormatTokensF(n int) string  { return "" }
+func (m *mockRenderer) FormatCost(f float64) string { return "" }
+func (m *mockRenderer) Icon(name types.Icon) string { return types.EmojiIcons[name] }
+
+func TestAssemble(t *testing.T) {
+	r := &mockRenderer{}
+
+	tests := []struct {
+		name     string
+		sections []Section
+		want     string
+	}{
+		{
+			name: "separators between sections",
+			sections: []Section{
+				{Name: Model, Text: "Opus 4.6"},
+				{Name: Context, Text: "Ctx: 30%"},
+			},
+			want: "Opus 4.6  │  Ctx: 30%",
+		},
+		{
+			name: "compact and space glue",
+			sections: []Section{
+				{Name: Duration, Text: "12m"},
+				{Name: Lines, Text: "+1/-2", Glue: GlueCompact},
+				{Name: Update, Text: "[Update]", Glue: GlueSpace},
+			},
+			want: "12m │ +1/-2 [Update]",
+		},
+		{
+			name: "empty sections are skipped",
+			sections: []Section{
+				{Name: Model, Text: "Opus 4.6"},
+				{Name: Ollama, Text: ""},
+				{Name: Duration, Text: "12m"},
+			},
+			want: "Opus 4.6  │  12m",
+		},
+		{
+			name: "first visible section has no leading glue",
+			sections: []Section{
+				{Name: Ollama, Text: ""},
+				{Name: Lines, Text: "+1/-2", Glue: GlueCompact},
+			},
+			want: "+1/-2",
+		},
+		{
+			name:     "nothing to show",
+			sections: nil,
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Assemble(tt.sections, r)
+			if got != tt.want {
+				t.Errorf("Assemble = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// blockRenderer records the blocks it is asked to join.
+type blockRenderer struct {
+	mockRenderer
+	blocks []ports.Block
+}
+
+func (b *blockRenderer) JoinBlocks(blocks []ports.Block) string {
+	b.blocks = blocks
+	var parts []string
+	for _, blk := range blocks {
+		parts = append(parts, "["+blk.Text+"]")
+	}
+	return strings.Join(parts, ">")
+}
+
+func TestAssembleBlockRenderer(t *testing.T) {
+	r := &blockRenderer{}
+	sections := []Section{
+		{Name: Model, Text: "Opus 4.6"},
+		{Name: Ollama, Text: ""},
+		{Name: Duration, Text: "12m"},
+		{Name: Lines, Text: "+1/-2", Glue: GlueCompact},
+		{Name: Update, Text: "[Update]", Glue: GlueSpace},
+	}
+
+	if got, want := Assemble(sections, r), "[Opus 4.6]>[12m │ +1/-2 [Update]]"; got != want {
+		t.Errorf("Assemble = %q, want %q", got, want)
+	}
+	want := []ports.Block{
+		{Name: Model, Text: "Opus 4.6"},
+		{Name: Duration, Text: "12m │ +1/-2 [Update]"},
+	}
+	if !reflect.DeepEqual(r.blocks, want) {
+		t.Errorf("blocks = %+v, want %+v", r.blocks, want)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	cfg := types.DefaultConfig()
+
+	if got := Order(cfg, true); !reflect.DeepEqual(got, [][]string{DefaultOAuth}) {
+		t.Errorf("Order(oauth) = %v, want single line %v", got, DefaultOAuth)
+	}
+	if got := Order(cfg, false); !reflect.DeepEqual(got, [][]string{DefaultAPIKey}) {
+		t.Errorf("Order(api key) = %v, want single line %v", got, DefaultAPIKey)
+	}
+
+	cfg.Segments = [][]string{{Context, Model}, {SevenDay}}
+	if got := Order(cfg, true); !reflect.DeepEqual(got, cfg.Segments) {
+		t.Errorf("Order with SEGMENTS = %v, want %v", got, cfg.Segments)
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	if got := Standalone(true); !reflect.DeepEqual(got, []string{FiveHour, SevenDay}) {
+		t.Errorf("Standalone(oauth) = %v, want 5h and 7d", got)
+	}
+	if got := Standalone(false); got != nil {
+		t.Errorf("Standalone(api key) = %v, want none", got)
+	}
+}
+
+func TestGlueFor(t *testing.T) {
+	tests := []struct {
+		name string
+		want Glue
+	}{
+		{Model, GlueSeparator},
+		{FiveHour, GlueSeparator},
+		{Lines, GlueCompact},
+		{Update, GlueSpace},
+		{"custom", GlueSeparator},
+	}
+	for _, tt := range tests {
+		if got := GlueFor(tt.name); got != tt.want {
+			t.Errorf("GlueFor(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	r := &mockRenderer{}
+	line := func() []Section {
+		return []Section{
+			{Name: Model, Text: "Opus 4.6", Priority: PriorityFor(Model)},
+			{Name: Context, Text: "Ctx: ██░░ 30% (60K/200K)", Compact: "Ctx: 30%", Priority: PriorityFor(Context)},
+			{Name: SevenDay, Text: "7d: ██░░ 27% 0.3x", Compact: "7d: 27%", Priority: PriorityFor(SevenDay)},
+			{Name: Ollama, Text: "3 req | saved ~$0.50", Compact: "3 req", Priority: PriorityFor(Ollama)},
+			{Name: Update, Text: "[Update]", Glue: GlueSpace, Priority: PriorityFor(Update)},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"unlimited", 0, "Opus 4.6  │  Ctx: ██░░ 30% (60K/200K)  │  7d: ██░░ 27% 0.3x  │  3 req | saved ~$0.50 [Update]"},
+		{"fits as is", 93, "Opus 4.6  │  Ctx: ██░░ 30% (60K/200K)  │  7d: ██░░ 27% 0.3x  │  3 req | saved ~$0.50 [Update]"},
+		{"compacts ollama first", 90, "Opus 4.6  │  Ctx: ██░░ 30% (60K/200K)  │  7d: ██░░ 27% 0.3x  │  3 req [Update]"},
+		{"then compacts 7d", 75, "Opus 4.6  │  Ctx: ██░░ 30% (60K/200K)  │  7d: 27%  │  3 req [Update]"},
+		{"then compacts context", 60, "Opus 4.6  │  Ctx: 30%  │  7d: 27%  │  3 req [Update]"},
+		{"then drops update", 50, "Opus 4.6  │  Ctx: 30%  │  7d: 27%  │  3 req"},
+		{"then drops ollama", 40, "Opus 4.6  │  Ctx: 30%  │  7d: 27%"},
+		{"then drops 7d", 25, "Opus 4.6  │  Ctx: 30%"},
+		{"keeps most important even if too wide", 3, "Opus 4.6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Assemble(Fit(line(), r, tt.width), r)
+			if got != tt.want {
+				t.Errorf("Fit(%d) = %q, want %q", tt.width, got, tt.want)
+			}
+			if tt.width > 3 && VisibleWidth(got) > tt.width {
+				t.Errorf("Fit(%d) width = %d, exceeds budget", tt.width, VisibleWidth(got))
+			}
+		})
+	}
+}
+
+func TestFitDoesNotModifyInput(t *testing.T) {
+	r := &mockRenderer{}
+	sections := []Section{
+		{Name: Model, Text: "Opus 4.6", Priority: 100},
+		{Name: Context, Text: "Ctx: ██░░ 30%", Compact: "Ctx: 30%", Priority: 90},
+	}
+	Fit(sections, r, 10)
+	if sections[1].Text != "Ctx: ██░░ 30%" {
+		t.Errorf("input section mutated: %q", sections[1].Text)
+	}
+}
+
+func TestFitTiesShrinkFromTheEnd(t *testing.T) {
+	r := &mockRenderer{}
+	sections := []Section{
+		{Name: "a", Text: "aaaa", Priority: 50},
+		{Name: "b", Text: "bbbb", Priority: 50},
+		{Name: "c", Text: "cccc", Priority: 50},
+	}
+	if got, want := Assemble(Fit(sections, r, 14), r), "aaaa  │  bbbb"; got != want {
+		t.Errorf("Fit = %q, want %q", got, want)
+	}
+}
+
+// markupRenderer styles like tmux: "#[dim]│#[default]".
+type markupRenderer struct{ mockRenderer }
+
+func (m *markupRenderer) Dim(text string) string { return "#[dim]" + text + "#[default]" }
+func (m *markupRenderer) StripMarkup(s string) string {
+	return strings.NewReplacer("#[dim]", "", "#[fg=red]", "", "#[default]", "").Replace(s)
+}
+
+func TestFitIgnoresMarkup(t *testing.T) {
+	r := &markupRenderer{}
+	sections := []Section{
+		{Name: Model, Text: "#[fg=red]Opus 4.6#[default]", Priority: PriorityFor(Model)},
+		{Name: SevenDay, Text: "#[fg=red]7d: 27% 0.3x#[default]", Compact: "7d: 27%", Priority: PriorityFor(SevenDay)},
+	}
+	// "Opus 4.6  │  7d: 27% 0.3x" is 25 columns; the markup is not shown
+	if got := Fit(sections, r, 25); got[1].Text != sections[1].Text {
+		t.Errorf("Fit(25) = %+v, want both sections as is", got)
+	}
+	if got := Fit(sections, r, 24); got[1].Text != "7d: 27%" {
+		t.Errorf("Fit(24) = %+v, want 7d compacted", got)
+	}
+	if got := RenderedWidth(Assemble(sections, r), r); got != 25 {
+		t.Errorf("RenderedWidth = %d, want 25", got)
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"plain", 5},
+		{"\033[32mok\033[0m", 2},
+		{"\033[32m\033[41m▇\033[0m░", 2},
+		{"Ctx: ██░░", 9},
+		{"🔥 5.0K", 7},
+		{"🦙 Qwen3", 8},
+		{"💰 $0.42", 8},
+		{"⚠️", 2},
+		{"⚠︎", 1},
+		{"→45m", 4},
+		{"日本", 4},
+	}
+	for _, tt := range tests {
+		if got := VisibleWidth(tt.in); got != tt.want {
+			t.Errorf("VisibleWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityFor(t *testing.T) {
+	// Lower-value segments must shrink before the core ones.
+	order := []string{Update, Ollama, Lines, Duration, SevenDay, Burn, Daily, FiveHour, Context, Model}
+	for i := 1; i < len(order); i++ {
+		if PriorityFor(order[i-1]) >= PriorityFor(order[i]) {
+			t.Errorf("PriorityFor(%q) should be below PriorityFor(%q)", order[i-1], order[i])
+		}
+	}
+}
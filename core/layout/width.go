@@ -0,0 +1,107 @@
+package layout
+
+import "unicode"
+
+// VisibleWidth returns the number of terminal columns s occupies.
+// ANSI escape codes take no space; emoji and East Asian wide characters take two.
+func VisibleWidth(s string) int {
+	width := 0
+	prev := 0 // width of the previous rune, for variation selectors
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			// CSI sequences end with a letter (e.g. "\033[0m")
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				inEscape = false
+			}
+		case r == '\033':
+			inEscape = true
+		case r == 0xFE0F:
+			// Emoji presentation selector widens the preceding symbol (e.g. ⚠️)
+			if prev == 1 {
+				width++
+				prev = 2
+			}
+		default:
+			prev = runeWidth(r)
+			width += prev
+		}
+	}
+	return width
+}
+
+// runeWidth returns the column width of a single rune.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200B || r == 0x200C || r == 0x200D || r == 0xFE0E:
+		return 0
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// wideRanges lists emoji and East Asian wide/fullwidth code point ranges.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x231A, 0x231B},   // ⌚⌛
+	{0x23E9, 0x23EC},   // ⏩-⏬
+	{0x23F0, 0x23F3},   // ⏰-⏳
+	{0x25FD, 0x25FE},   // ◽◾
+	{0x2614, 0x2615},   // ☔☕
+	{0x26A1, 0x26A1},   // ⚡
+	{0x26AA, 0x26AB},   // ⚪⚫
+	{0x26BD, 0x26BE},   // ⚽⚾
+	{0x26C4, 0x26C5},   // ⛄⛅
+	{0x26D4, 0x26D4},   // ⛔
+	{0x26EA, 0x26EA},   // ⛪
+	{0x26F2, 0x26F5},   // ⛲-⛵
+	{0x26FA, 0x26FD},   // ⛺-⛽
+	{0x2705, 0x2705},   // ✅
+	{0x270A, 0x270B},   // ✊✋
+	{0x2728, 0x2728},   // ✨
+	{0x274C, 0x274C},   // ❌
+	{0x2753, 0x2755},   // ❓-❕
+	{0x2795, 0x2797},   // ➕-➗
+	{0x2B1B, 0x2B1C},   // ⬛⬜
+	{0x2B50, 0x2B50},   // ⭐
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F004, 0x1F004}, // 🀄
+	{0x1F0CF, 0x1F0CF}, // 🃏
+	{0x1F18E, 0x1F18E}, // 🆎
+	{0x1F191, 0x1F19A}, // 🆑-🆚
+	{0x1F200, 0x1F2FF}, // Enclosed ideographic supplement
+	{0x1F300, 0x1F64F}, // Misc symbols and pictographs, emoticons (🔥 💰 📅)
+	{0x1F680, 0x1F6FF}, // Transport and map symbols
+	{0x1F7E0, 0x1F7EB}, // Colored circles and squares
+	{0x1F900, 0x1FAFF}, // Supplemental symbols and pictographs (🦙)
+	{0x20000, 0x3FFFD}, // CJK extensions B+
+}
+
+func isWide(r rune) bool {
+	if r < 0x1100 {
+		return false
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			return false
+		}
+		if r <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -24,6 +24,21 @@ const (
 	FamilyOpus
 )
 
+// String returns the lowercase family name ("opus"), as used in rate limit
+// window names such as "7d-opus", or "" when unknown.
+func (f ModelFamily) String() string {
+	switch f {
+	case FamilyHaiku:
+		return "haiku"
+	case FamilySonnet:
+		return "sonnet"
+	case FamilyOpus:
+		return "opus"
+	default:
+		return ""
+	}
+}
+
 // CostWeight returns the cost weight for a model family from config.
 func CostWeight(family ModelFamily, cfg types.Config) float64 {
 	switch family {
@@ -64,6 +79,7 @@ func Resolve(modelID, displayName string, ollama ports.OllamaClient, cacheDir st
 			DefaultContext: defaultClaudeContext,
 			IsLocal:        false,
 			CostWeight:     CostWeight(family, cfg),
+			Family:         family.String(),
 		}
 	}
 
@@ -83,6 +99,7 @@ func Resolve(modelID, displayName string, ollama ports.OllamaClient, cacheDir st
 		DefaultContext: defaultClaudeContext,
 		IsLocal:        false,
 		CostWeight:     CostWeight(family, cfg),
+		Family:         family.String(),
 	}
 }
 
@@ -53,6 +53,23 @@ func TestResolveClaudeModels(t *testing.T) {
 	}
 }
 
+func TestResolveFamily(t *testing.T) {
+	tests := []struct {
+		modelID, displayName, want string
+	}{
+		{"claude-opus-4-6", "Opus", "opus"},
+		{"claude-sonnet-4-6", "Sonnet", "sonnet"},
+		{"claude-3-5-haiku-20241022", "", "haiku"},
+		{"future-model", "Claude Opus 5", "opus"},
+		{"future-model", "Mystery", ""},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.modelID, tt.displayName, nil, "", types.DefaultConfig()).Family; got != tt.want {
+			t.Errorf("Resolve(%q, %q).Family = %q, want %q", tt.modelID, tt.displayName, got, tt.want)
+		}
+	}
+}
+
 func TestResolveOllamaModels(t *testing.T) {
 	tests := []struct {
 		modelID   string
@@ -60,8 +60,8 @@ func Savings(stats *Stats) float64 {
 }
 
 // Render formats Ollama stats for display in the statusline.
-// Returns empty string if stats are nil or empty.
-func Render(stats *Stats) string {
+// Savings are formatted by formatCost. Returns empty string if stats are nil or empty.
+func Render(stats *Stats, formatCost func(float64) string) string {
 	if stats == nil || stats.Requests == 0 {
 		return ""
 	}
@@ -70,5 +70,14 @@ func Render(stats *Stats) string {
 	if saved < 0.01 {
 		return fmt.Sprintf("%d req", stats.Requests)
 	}
-	return fmt.Sprintf("%d req | saved ~$%.2f", stats.Requests, saved)
+	return fmt.Sprintf("%d req | saved ~%s", stats.Requests, formatCost(saved))
+}
+
+// RenderCompact formats only the request count, e.g. "42 req".
+// Returns empty string if stats are nil or empty.
+func RenderCompact(stats *Stats) string {
+	if stats == nil || stats.Requests == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d req", stats.Requests)
 }
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
 )
 
 func writeTestStats(t *testing.T, dir string, stats Stats) string {
@@ -165,10 +167,20 @@ func TestRender(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := Render(tt.stats)
+			got := Render(tt.stats, types.Locale{}.Cost)
 			if got != tt.want {
 				t.Errorf("Render() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestRenderCompact(t *testing.T) {
+	if got := RenderCompact(nil); got != "" {
+		t.Errorf("RenderCompact(nil) = %q, want empty", got)
+	}
+	stats := &Stats{Requests: 42, TotalPromptTokens: 1_000_000, TotalCompletionTokens: 200_000}
+	if got, want := RenderCompact(stats), "42 req"; got != want {
+		t.Errorf("RenderCompact() = %q, want %q", got, want)
+	}
+}
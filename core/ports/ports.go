@@ -17,6 +17,7 @@ type CacheStore interface {
 	ReadIfFresh(path string, ttl time.Duration) ([]byte, bool)
 	ReadFile(path string) ([]byte, error)
 	WriteFile(path string, data []byte) error
+	AppendFile(path string, data []byte) error // Single write in append mode: small records don't interleave
 	FileMTime(path string) (time.Time, error)
 	CleanOld(dir, pattern, keep string) error
 }
@@ -29,14 +30,35 @@ type APIClient interface {
 
 // Renderer produces ANSI-formatted output.
 type Renderer interface {
-	Colorize(text string, percent int) string
-	Color(text, color string) string
+	Style(text string, role types.Role) string // Color text by semantic role (see types.Role*)
 	Dim(text string) string
-	MakeBar(percent, width int) string
-	MakeSplitBar(usagePct, timePct, width int) string
+	MakeBar(percent int, bar types.BarStyle) string
+	MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string
 	FormatTokens(n int) string  // 0 decimals: "200K"
 	FormatTokensF(n int) string // 1 decimal:  "100.0K"
 	FormatCost(f float64) string
+	Icon(name types.Icon) string // Glyph from the icon set, "" if the set has none
+}
+
+// Block is a run of sections drawn together, e.g. on one background color.
+type Block struct {
+	Name string // Name of the block's first section
+	Text string
+}
+
+// BlockRenderer is implemented by renderers that draw segments as blocks with
+// their own transitions (e.g. Powerline arrows) instead of "│" separators.
+type BlockRenderer interface {
+	Renderer
+	JoinBlocks(blocks []Block) string
+}
+
+// MarkupRenderer is implemented by renderers that style text with inline
+// markup instead of escape codes (e.g. tmux "#[fg=green]"), so that the
+// layout can measure the visible text.
+type MarkupRenderer interface {
+	Renderer
+	StripMarkup(s string) string
 }
 
 // PlatformInfo provides OS-specific operations.
@@ -61,3 +83,49 @@ type AgentCounter interface {
 type OllamaClient interface {
 	GetContextSize(model string) (int, error)
 }
+
+// Segment is a named, individually renderable statusline section.
+type Segment interface {
+	// Name is the identifier used in the SEGMENTS config key.
+	Name() string
+	// Dependencies lists the shared data keys the segment reads from the RenderContext.
+	Dependencies() []string
+	// Render produces the section text, or "" to hide the section.
+	Render(rc *RenderContext) string
+}
+
+// CompactSegment is implemented by segments with a shorter form that the
+// layout falls back to when a line does not fit the available width.
+type CompactSegment interface {
+	Segment
+	RenderCompact(rc *RenderContext) string
+}
+
+// RenderContext is the shared per-render state handed to every Segment.
+type RenderContext struct {
+	Input    types.Input
+	Model    types.ModelInfo
+	Config   types.Config
+	Renderer Renderer
+
+	Credentials types.Credentials
+	Platform    PlatformInfo
+	Store       CacheStore
+	API         APIClient
+
+	values map[string]any
+}
+
+// Value returns the shared value stored under key, computing it on first use.
+// This lets several segments share one expensive computation per render.
+func (rc *RenderContext) Value(key string, compute func() any) any {
+	if v, ok := rc.values[key]; ok {
+		return v
+	}
+	if rc.values == nil {
+		rc.values = make(map[string]any)
+	}
+	v := compute()
+	rc.values[key] = v
+	return v
+}
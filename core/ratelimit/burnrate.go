@@ -43,9 +43,10 @@ func CalculateBurnRate(input types.Input, cfg types.Config) types.BurnInfo {
 // CalculateGlobalBurnFromStdin computes account-wide burn rate from stdin rate_limits deltas.
 // On each render, it reads the previous snapshot, calculates the TPM delta, and writes
 // the new snapshot. This replaces the daemon for global burn rate tracking.
-func CalculateGlobalBurnFromStdin(currentPct float64, cfg types.Config, store ports.CacheStore) types.BurnInfo {
+// Snapshots are kept per account key (see types.Credentials.AccountKey).
+func CalculateGlobalBurnFromStdin(currentPct float64, account string, cfg types.Config, store ports.CacheStore) types.BurnInfo {
 	var info types.BurnInfo
-	cachePath := fmt.Sprintf("%s/%s", cfg.CacheDir, globalBurnFile)
+	cachePath := fmt.Sprintf("%s/%s", cfg.CacheDir, types.AccountFile(globalBurnFile, account))
 	now := time.Now().Unix()
 
 	// Read previous snapshot
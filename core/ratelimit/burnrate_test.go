@@ -43,7 +43,7 @@ func TestCalculateGlobalBurnFromStdin_FirstCall(t *testing.T) {
 	cfg := types.DefaultConfig()
 
 	// First call: no previous snapshot → TPM=0, but snapshot is written
-	info := CalculateGlobalBurnFromStdin(42.0, cfg, store)
+	info := CalculateGlobalBurnFromStdin(42.0, "", cfg, store)
 	if info.GlobalTPM != 0 {
 		t.Errorf("First call should have GlobalTPM=0, got %f", info.GlobalTPM)
 	}
@@ -70,7 +70,7 @@ func TestCalculateGlobalBurnFromStdin_WithDelta(t *testing.T) {
 	store.files[cachePath] = raw
 
 	// Now at 42% → delta = 2% in 60s = 2%/min → 10000 t/m
-	info := CalculateGlobalBurnFromStdin(42.0, cfg, store)
+	info := CalculateGlobalBurnFromStdin(42.0, "", cfg, store)
 	if info.GlobalTPM < 9000 || info.GlobalTPM > 11000 {
 		t.Errorf("GlobalTPM should be ~10000 (2%%/min * 5000), got %f", info.GlobalTPM)
 	}
@@ -91,7 +91,7 @@ func TestCalculateGlobalBurnFromStdin_Decay(t *testing.T) {
 	store.files[cachePath] = raw
 
 	// Same % → no delta → decay by 50%
-	info := CalculateGlobalBurnFromStdin(40.0, cfg, store)
+	info := CalculateGlobalBurnFromStdin(40.0, "", cfg, store)
 	if info.GlobalTPM < 4500 || info.GlobalTPM > 5500 {
 		t.Errorf("GlobalTPM should decay to ~5000 (50%% of 10000), got %f", info.GlobalTPM)
 	}
@@ -112,7 +112,7 @@ func TestCalculateGlobalBurnFromStdin_Smoothing(t *testing.T) {
 	store.files[cachePath] = raw
 
 	// Now at 42% → new = 10000, smoothed = 0.8*10000 + 0.2*8000 = 9600
-	info := CalculateGlobalBurnFromStdin(42.0, cfg, store)
+	info := CalculateGlobalBurnFromStdin(42.0, "", cfg, store)
 	if info.GlobalTPM < 9000 || info.GlobalTPM > 10000 {
 		t.Errorf("GlobalTPM should be ~9600 (smoothed), got %f", info.GlobalTPM)
 	}
@@ -5,7 +5,6 @@ import (
 	"math"
 	"time"
 
-	"github.com/Benniphx/claude-statusline/adapter/render"
 	"github.com/Benniphx/claude-statusline/core/ports"
 	"github.com/Benniphx/claude-statusline/core/types"
 )
@@ -18,7 +17,10 @@ func CalculatePace(data types.RateLimitData, cfg types.Config, plat ports.Platfo
 	pace := types.PaceInfo{}
 
 	// 5-hour pace
-	pace.FiveHourPace, pace.HittingLimit, pace.LimitETA, pace.ResetInfo = calcFiveHourPace(data, now)
+	pace.FiveHourPace, pace.HittingLimit, pace.LimitAt, pace.ResetIn, pace.ResetAt = calcFiveHourPace(data, cfg.Locale, now)
+	if pace.HittingLimit {
+		pace.LimitETA = cfg.Locale.Time(pace.LimitAt)
+	}
 
 	// 5h time percentage: how much of the 5h window has elapsed
 	remainingSecs5h := data.FiveHourReset.Sub(now).Seconds()
@@ -34,7 +36,7 @@ func CalculatePace(data types.RateLimitData, cfg types.Config, plat ports.Platfo
 	}
 
 	// 7-day pace
-	pace.SevenDayPace, pace.SevenDayResetFmt = calcSevenDayPace(data, cfg, plat, now)
+	pace.SevenDayPace, pace.SevenDayResetIn = calcSevenDayPace(data, cfg, plat, now)
 
 	// 7d time percentage: how much of the 7d window has elapsed
 	remainingSecs7d := data.SevenDayReset.Sub(now).Seconds()
@@ -53,7 +55,7 @@ func CalculatePace(data types.RateLimitData, cfg types.Config, plat ports.Platfo
 	return pace
 }
 
-func calcFiveHourPace(data types.RateLimitData, now time.Time) (pace float64, hitting bool, limitETA string, resetInfo string) {
+func calcFiveHourPace(data types.RateLimitData, l types.Locale, now time.Time) (pace float64, hitting bool, limitAt time.Time, resetIn, resetAt string) {
 	remainingSecs := data.FiveHourReset.Sub(now).Seconds()
 	if remainingSecs < 0 {
 		remainingSecs = 0
@@ -69,12 +71,12 @@ func calcFiveHourPace(data types.RateLimitData, now time.Time) (pace float64, hi
 
 	secsSinceStart := float64(fiveHourSecs) - remainingSecs
 	if secsSinceStart <= 0 {
-		return 0, false, "", ""
+		return 0, false, time.Time{}, "", ""
 	}
 
 	hoursSinceStart := secsSinceStart / 3600.0
 	if hoursSinceStart <= 0 {
-		return 0, false, "", ""
+		return 0, false, time.Time{}, "", ""
 	}
 
 	percentPerHour := data.FiveHourPercent / hoursSinceStart
@@ -88,8 +90,7 @@ func calcFiveHourPace(data types.RateLimitData, now time.Time) (pace float64, hi
 		if runwaySecs < remainingSecs {
 			hitting = true
 			// Calculate when limit will be hit: now + runwayMin
-			etaTime := now.Add(time.Duration(runwayMin) * time.Minute)
-			limitETA = etaTime.Format("15:04")
+			limitAt = now.Add(time.Duration(runwayMin) * time.Minute)
 		}
 	}
 
@@ -104,26 +105,20 @@ func calcFiveHourPace(data types.RateLimitData, now time.Time) (pace float64, hi
 		resetTimeStr = fmt.Sprintf("%dm", resetM)
 	}
 
-	// Show reset info based on remaining time
-	// Format: DIM→RESETCYAN{time}RESET [DIM@RESETCYAN{local}RESET]
+	// Show reset info based on remaining time: countdown within the last hour,
+	// plus the local reset time within the last 30 minutes
 	remainingMin := remainingSecsRounded / 60.0
+	if remainingMin <= 60 {
+		resetIn = resetTimeStr
+	}
 	if remainingMin <= 30 {
-		localTime := time.Unix(resetRounded, 0).Format("15:04")
-		resetInfo = fmt.Sprintf("%s→%s%s%s %s@%s%s%s",
-			render.DimCode, render.Reset,
-			render.Cyan, resetTimeStr+render.Reset,
-			render.DimCode, render.Reset,
-			render.Cyan, localTime+render.Reset)
-	} else if remainingMin <= 60 {
-		resetInfo = fmt.Sprintf("%s→%s%s%s%s",
-			render.DimCode, render.Reset,
-			render.Cyan, resetTimeStr, render.Reset)
-	}
-
-	return pace, hitting, limitETA, resetInfo
+		resetAt = l.Time(time.Unix(resetRounded, 0))
+	}
+
+	return pace, hitting, limitAt, resetIn, resetAt
 }
 
-func calcSevenDayPace(data types.RateLimitData, cfg types.Config, plat ports.PlatformInfo, now time.Time) (pace float64, resetFmt string) {
+func calcSevenDayPace(data types.RateLimitData, cfg types.Config, plat ports.PlatformInfo, now time.Time) (pace float64, resetIn string) {
 	remainingSecs := data.SevenDayReset.Sub(now).Seconds()
 	if remainingSecs < 0 {
 		remainingSecs = 0
@@ -145,18 +140,14 @@ func calcSevenDayPace(data types.RateLimitData, cfg types.Config, plat ports.Pla
 	pace = actualPerDay / sustainablePerDay
 	pace = math.Round(pace*10) / 10
 
-	// Format reset: "→Xd" with dim/cyan coloring, only when ≤3 days
+	// Days until reset, only when ≤3 days
 	if daysLeft <= 3 {
-		var daysStr string
 		if daysLeft > 0 {
-			daysStr = fmt.Sprintf("%dd", daysLeft)
+			resetIn = fmt.Sprintf("%dd", daysLeft)
 		} else {
-			daysStr = "<1d"
+			resetIn = "<1d"
 		}
-		resetFmt = fmt.Sprintf("%s→%s%s%s%s",
-			render.DimCode, render.Reset,
-			render.Cyan, daysStr, render.Reset)
 	}
 
-	return pace, resetFmt
+	return pace, resetIn
 }
@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -53,7 +54,7 @@ func TestCalcFiveHourPace(t *testing.T) {
 				FiveHourReset:   now.Add(tt.resetIn),
 			}
 
-			pace, hitting, _, _ := calcFiveHourPace(data, now)
+			pace, hitting, _, _, _ := calcFiveHourPace(data, types.Locale{}, now)
 
 			diff := pace - tt.wantPace
 			if diff < -0.1 || diff > 0.1 {
@@ -121,11 +122,11 @@ func TestLimitETA(t *testing.T) {
 		FiveHourPercent: 60,
 		FiveHourReset:   now.Add(4 * time.Hour),
 	}
-	_, hitting, eta, _ := calcFiveHourPace(data, now)
+	_, hitting, eta, _, _ := calcFiveHourPace(data, types.Locale{}, now)
 	if !hitting {
 		t.Error("should be hitting limit at 60% in 1h")
 	}
-	if eta == "" {
+	if eta.IsZero() {
 		t.Error("should show limit ETA when hitting limit")
 	}
 
@@ -134,12 +135,12 @@ func TestLimitETA(t *testing.T) {
 		FiveHourPercent: 10,
 		FiveHourReset:   now.Add(3 * time.Hour),
 	}
-	_, hittingSlow, etaSlow, _ := calcFiveHourPace(dataSlow, now)
+	_, hittingSlow, etaSlow, _, _ := calcFiveHourPace(dataSlow, types.Locale{}, now)
 	if hittingSlow {
 		t.Error("should not be hitting limit at 10% in 2h")
 	}
-	if etaSlow != "" {
-		t.Errorf("should not show ETA when not hitting limit, got %q", etaSlow)
+	if !etaSlow.IsZero() {
+		t.Errorf("should not show ETA when not hitting limit, got %v", etaSlow)
 	}
 }
 
@@ -151,12 +152,26 @@ func TestResetTimeDisplay(t *testing.T) {
 		FiveHourPercent: 50,
 		FiveHourReset:   now.Add(25 * time.Minute),
 	}
-	_, _, _, info30 := calcFiveHourPace(data30, now)
-	if info30 == "" {
-		t.Error("should show reset info for ≤30 min")
+	_, _, _, in30, at30 := calcFiveHourPace(data30, types.Locale{}, now)
+	if in30 == "" || at30 == "" {
+		t.Errorf("should show countdown and clock for ≤30 min, got %q @%q", in30, at30)
 	}
-	if len(info30) < 5 { // At minimum "→Xm @HH:MM"
-		t.Errorf("reset info too short: %q", info30)
+	if len(at30) != 5 { // "HH:MM"
+		t.Errorf("reset clock should be HH:MM, got %q", at30)
+	}
+	_, _, _, _, at12h := calcFiveHourPace(data30, types.Locale{Clock: "12h"}, now)
+	if !strings.HasSuffix(at12h, "am") && !strings.HasSuffix(at12h, "pm") {
+		t.Errorf("12h reset clock should end in am/pm, got %q", at12h)
+	}
+
+	// ≤60 min: countdown only
+	data45 := types.RateLimitData{
+		FiveHourPercent: 50,
+		FiveHourReset:   now.Add(45 * time.Minute),
+	}
+	_, _, _, in45, at45 := calcFiveHourPace(data45, types.Locale{}, now)
+	if in45 == "" || at45 != "" {
+		t.Errorf("should show countdown only for ≤60 min, got %q @%q", in45, at45)
 	}
 
 	// >60 min: no time shown
@@ -164,8 +179,8 @@ func TestResetTimeDisplay(t *testing.T) {
 		FiveHourPercent: 50,
 		FiveHourReset:   now.Add(90 * time.Minute),
 	}
-	_, _, _, info90 := calcFiveHourPace(data90, now)
-	if info90 != "" {
-		t.Errorf("should not show reset info for >60 min: %q", info90)
+	_, _, _, in90, at90 := calcFiveHourPace(data90, types.Locale{}, now)
+	if in90 != "" || at90 != "" {
+		t.Errorf("should not show reset info for >60 min: %q @%q", in90, at90)
 	}
 }
@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/Benniphx/claude-statusline/adapter/render"
+	"github.com/Benniphx/claude-statusline/core/compat"
+	"github.com/Benniphx/claude-statusline/core/history"
 	"github.com/Benniphx/claude-statusline/core/ports"
 	"github.com/Benniphx/claude-statusline/core/types"
 )
 
+// cacheName is the API response cache, one per account (see types.AccountFile).
 const cacheName = "claude_rate_limit_cache.json"
 
 // RateSections holds the three rate limit display sections.
@@ -21,30 +24,22 @@ type RateSections struct {
 	SevenDay string
 }
 
-// Load retrieves rate limit data, using cache or fetching from the API.
+// Load retrieves rate limit data, using the account's cache or fetching from the API.
 func Load(creds types.Credentials, cfg types.Config, store ports.CacheStore, api ports.APIClient) (types.RateLimitData, error) {
-	cachePath := fmt.Sprintf("%s/%s", cfg.CacheDir, cacheName)
+	cachePath := cacheFile(creds, cfg)
 
 	// STATUSLINE_NO_POLL=1 → only serve cache, never call API
 	noPoll := os.Getenv("STATUSLINE_NO_POLL") == "1"
 
 	// Try cache first
-	if data, fresh := store.ReadIfFresh(cachePath, cfg.RateCacheTTL); fresh {
-		var resp types.RateLimitResponse
-		if err := json.Unmarshal(data, &resp); err == nil {
-			return parseResponse(&resp)
-		}
+	if result, ok := readFresh(store, cachePath, cfg.RateCacheTTL); ok {
+		return result, nil
 	}
 
 	// If polling disabled, try stale cache then give up
 	if noPoll {
-		if data, err := store.ReadFile(cachePath); err == nil {
-			var stale types.RateLimitResponse
-			if err2 := json.Unmarshal(data, &stale); err2 == nil {
-				result, _ := parseResponse(&stale)
-				result.FromCache = true
-				return result, nil
-			}
+		if result, ok := readStale(store, cachePath); ok {
+			return result, nil
 		}
 		return types.RateLimitData{}, fmt.Errorf("polling disabled (STATUSLINE_NO_POLL=1)")
 	}
@@ -57,13 +52,8 @@ func Load(creds types.Credentials, cfg types.Config, store ports.CacheStore, api
 	resp, err := api.FetchRateLimits(creds.OAuthToken)
 	if err != nil {
 		// Try stale cache as fallback
-		if data, err2 := store.ReadFile(cachePath); err2 == nil {
-			var stale types.RateLimitResponse
-			if err3 := json.Unmarshal(data, &stale); err3 == nil {
-				result, _ := parseResponse(&stale)
-				result.FromCache = true
-				return result, nil
-			}
+		if result, ok := readStale(store, cachePath); ok {
+			return result, nil
 		}
 		return types.RateLimitData{}, err
 	}
@@ -73,19 +63,128 @@ func Load(creds types.Credentials, cfg types.Config, store ports.CacheStore, api
 		store.AtomicWrite(cachePath, raw)
 	}
 
-	return parseResponse(resp)
+	result, _ := parseResponse(resp)
+	result.Source = types.RateSourceAPI
+	result.FetchedAt = time.Now()
+	return result, nil
+}
+
+// LoadCached retrieves rate limit data from the account's cache, fresh or
+// stale, without calling the API. The data is marked FromCache either way:
+// it was written by another session, not received by this one.
+func LoadCached(creds types.Credentials, cfg types.Config, store ports.CacheStore) (types.RateLimitData, error) {
+	cachePath := cacheFile(creds, cfg)
+	if result, ok := readFresh(store, cachePath, cfg.RateCacheTTL); ok {
+		result.FromCache = true
+		return result, nil
+	}
+	if result, ok := readStale(store, cachePath); ok {
+		return result, nil
+	}
+	return types.RateLimitData{}, fmt.Errorf("no cached rate limits")
+}
+
+// cacheStdin stores the stdin rate_limits in the account's cache in the API
+// response format, for sessions that have not received rate_limits yet. It
+// writes at most once per RateCacheTTL.
+func cacheStdin(rl *types.StdinRateLimits, creds types.Credentials, cfg types.Config, store ports.CacheStore) {
+	cachePath := cacheFile(creds, cfg)
+	if _, fresh := store.ReadIfFresh(cachePath, cfg.RateCacheTTL); fresh {
+		return
+	}
+	resp := types.RateLimitResponse{
+		FiveHour: types.RateLimitWindow{Utilization: rl.FiveHour.UsedPercentage, ResetsAt: rl.FiveHour.ResetsAt},
+		SevenDay: types.RateLimitWindow{Utilization: rl.SevenDay.UsedPercentage, ResetsAt: rl.SevenDay.ResetsAt},
+		Extra:    rl.ExtraUsage,
+	}
+	if raw, err := json.Marshal(resp); err == nil {
+		store.AtomicWrite(cachePath, raw)
+	}
+}
+
+// cacheFile returns the path of the account's API response cache.
+func cacheFile(creds types.Credentials, cfg types.Config) string {
+	return fmt.Sprintf("%s/%s", cfg.CacheDir, types.AccountFile(cacheName, creds.AccountKey()))
+}
+
+// readFresh reads the cached API response if younger than ttl.
+func readFresh(store ports.CacheStore, cachePath string, ttl time.Duration) (types.RateLimitData, bool) {
+	data, fresh := store.ReadIfFresh(cachePath, ttl)
+	if !fresh {
+		return types.RateLimitData{}, false
+	}
+	var resp types.RateLimitResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return types.RateLimitData{}, false
+	}
+	result, _ := parseResponse(&resp)
+	result.Source = types.RateSourceCache
+	result.FetchedAt, _ = store.FileMTime(cachePath)
+	return result, true
+}
+
+// readStale reads the cached API response regardless of its age.
+func readStale(store ports.CacheStore, cachePath string) (types.RateLimitData, bool) {
+	data, err := store.ReadFile(cachePath)
+	if err != nil {
+		return types.RateLimitData{}, false
+	}
+	var stale types.RateLimitResponse
+	if err := json.Unmarshal(data, &stale); err != nil {
+		return types.RateLimitData{}, false
+	}
+	result, _ := parseResponse(&stale)
+	result.FromCache = true
+	result.Source = types.RateSourceCache
+	result.FetchedAt, _ = store.FileMTime(cachePath)
+	return result, true
 }
 
 func parseResponse(resp *types.RateLimitResponse) (types.RateLimitData, error) {
 	fiveHourReset, _ := time.Parse(time.RFC3339, resp.FiveHour.ResetsAt)
 	sevenDayReset, _ := time.Parse(time.RFC3339, resp.SevenDay.ResetsAt)
 
-	return types.RateLimitData{
+	data := types.RateLimitData{
 		FiveHourPercent: resp.FiveHour.Utilization,
 		FiveHourReset:   fiveHourReset,
 		SevenDayPercent: resp.SevenDay.Utilization,
 		SevenDayReset:   sevenDayReset,
-	}, nil
+	}
+	data.Windows = primaryWindows(data)
+	for _, key := range resp.WindowKeys() {
+		w := resp.Windows[key]
+		reset, _ := time.Parse(time.RFC3339, w.ResetsAt)
+		data.Windows = append(data.Windows, types.RateWindow{Name: types.WindowName(key), Percent: w.Utilization, Reset: reset})
+	}
+	data.Extra = extraUsage(resp.Extra)
+	return data, nil
+}
+
+// extraUsage converts the extra_usage amounts from cents to USD. Without
+// used_credits the spend is derived from the utilization of the limit.
+func extraUsage(resp *types.ExtraUsageResponse) types.ExtraUsage {
+	if resp == nil || !resp.IsEnabled {
+		return types.ExtraUsage{}
+	}
+	extra := types.ExtraUsage{Enabled: true}
+	if resp.MonthlyLimit != nil {
+		extra.Limit = *resp.MonthlyLimit / 100
+	}
+	switch {
+	case resp.UsedCredits != nil:
+		extra.Used = *resp.UsedCredits / 100
+	case resp.Utilization != nil:
+		extra.Used = *resp.Utilization / 100 * extra.Limit
+	}
+	return extra
+}
+
+// primaryWindows lists the 5h and 7d windows of data.
+func primaryWindows(data types.RateLimitData) []types.RateWindow {
+	return []types.RateWindow{
+		{Name: types.WindowFiveHour, Percent: data.FiveHourPercent, Reset: data.FiveHourReset},
+		{Name: types.WindowSevenDay, Percent: data.SevenDayPercent, Reset: data.SevenDayReset},
+	}
 }
 
 // LoadFromStdin creates RateLimitData from the stdin rate_limits field (Claude Code ≥2.1.80).
@@ -103,69 +202,228 @@ func LoadFromStdin(rl *types.StdinRateLimits) (types.RateLimitData, error) {
 		return types.RateLimitData{}, fmt.Errorf("invalid seven_day.resets_at: %w", err)
 	}
 
-	return types.RateLimitData{
+	data := types.RateLimitData{
 		FiveHourPercent: rl.FiveHour.UsedPercentage,
 		FiveHourReset:   fiveHourReset,
 		SevenDayPercent: rl.SevenDay.UsedPercentage,
 		SevenDayReset:   sevenDayReset,
-	}, nil
+		Source:          types.RateSourceStdin,
+		FetchedAt:       time.Now(),
+		Extra:           extraUsage(rl.ExtraUsage),
+	}
+	data.Windows = primaryWindows(data)
+	return data, nil
 }
 
-// RenderSections produces the three rate limit sections for assembly by main.go.
-func RenderSections(input types.Input, creds types.Credentials, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, api ports.APIClient, r ports.Renderer, modelInfo types.ModelInfo) RateSections {
+// State holds the computed rate limit data shared by the 5h, burn and 7d sections.
+type State struct {
+	Data   types.RateLimitData
+	Weekly types.RateWindow // 7d window driving the 7d section and its warnings (see RateLimitData.Weekly)
+	Pace   types.PaceInfo   // 7d figures are those of Weekly
+	Burn   types.BurnInfo
+	Norm   types.CostNorm
+	Err    error // Non-nil when no rate limit data could be loaded
+}
+
+// SevenDay returns the 7d window driving the 7d section: Weekly, or the shared
+// 7d window when Weekly is unset.
+func (st State) SevenDay() types.RateWindow {
+	if st.Weekly.Name != "" {
+		return st.Weekly
+	}
+	return st.Data.Weekly("")
+}
+
+// Compute loads rate limit data and derives pace and burn metrics.
+func Compute(input types.Input, creds types.Credentials, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, api ports.APIClient, modelInfo types.ModelInfo) State {
 	// Prefer stdin rate_limits (Claude Code ≥2.1.80) — always fresh, no API call needed
 	data, err := LoadFromStdin(input.RateLimits)
-	if err != nil {
-		// Fallback to API/cache
+	if err == nil {
+		cacheStdin(input.RateLimits, creds, cfg, store)
+	} else if native, known := compat.Supports(input.Version, compat.RateLimits); known && native {
+		// rate_limits is left out until the first response of a session:
+		// show what an earlier session left in the cache, and poll only
+		// when there is none yet
+		data, err = LoadCached(creds, cfg, store)
+		if err != nil {
+			data, err = Load(creds, cfg, store, api)
+		}
+	} else {
+		// Older or unknown Claude Code: fallback to API/cache
 		data, err = Load(creds, cfg, store, api)
 	}
 	if err != nil {
-		return RateSections{
-			FiveHour: "5h: " + r.Dim("--"),
-			Burn:     "🔥 " + r.Dim("--"),
-			SevenDay: "7d: " + r.Dim("--"),
-		}
+		return State{Err: err}
 	}
 
-	cn := types.ResolveCostNorm(cfg, modelInfo)
+	// Burn snapshots are kept per account, like the cache
+	account := creds.AccountKey()
 
-	pace := CalculatePace(data, cfg, plat)
 	localBurn := CalculateBurnRate(input, cfg)
 
 	// Global burn from stdin-delta (no daemon needed)
-	globalBurn := CalculateGlobalBurnFromStdin(data.FiveHourPercent, cfg, store)
-	burn := MergeLocalGlobal(localBurn, globalBurn)
+	globalBurn := CalculateGlobalBurnFromStdin(data.FiveHourPercent, account, cfg, store)
+
+	// The weekly window that runs out first for the active model drives the
+	// 7d pace and warnings
+	weekly := data.Weekly(modelInfo.Family)
+	paceData := data
+	paceData.SevenDayPercent, paceData.SevenDayReset = weekly.Percent, weekly.Reset
+
+	return State{
+		Data:   data,
+		Weekly: weekly,
+		Pace:   CalculatePace(paceData, cfg, plat),
+		Burn:   MergeLocalGlobal(localBurn, globalBurn),
+		Norm:   types.ResolveCostNorm(cfg, modelInfo),
+	}
+}
+
+// History returns the account's recorded usage, adding st's data to it when
+// live: cached data is not from now. It is kept apart from Compute so only
+// the sections showing the history or the forecast read the file.
+func History(st State, creds types.Credentials, cfg types.Config, store ports.CacheStore, now time.Time) []history.Point {
+	if st.Err != nil {
+		return nil
+	}
+	if st.Data.Source != types.RateSourceCache {
+		return history.Record(st.Data, creds.AccountKey(), cfg, store, now)
+	}
+	return history.Load(creds.AccountKey(), cfg, store, now)
+}
+
+// Forecast projects the weekly limit from the recorded usage. The history only
+// tracks the shared 7d window, so there is none while a per-model one drives 7d.
+func Forecast(st State, points []history.Point, now time.Time) history.Forecast {
+	if st.Err != nil || st.SevenDay().Name != types.WindowSevenDay {
+		return history.Forecast{}
+	}
+	return history.SevenDayForecast(points, st.Data, now)
+}
 
+// RenderSections produces the three rate limit sections for assembly by main.go.
+func RenderSections(input types.Input, creds types.Credentials, cfg types.Config, plat ports.PlatformInfo, store ports.CacheStore, api ports.APIClient, r ports.Renderer, modelInfo types.ModelInfo) RateSections {
+	st := Compute(input, creds, cfg, plat, store, api, modelInfo)
+	now := time.Now()
+	fc := Forecast(st, History(st, creds, cfg, store, now), now)
 	return RateSections{
-		FiveHour: renderFiveHour(data, pace, cn, r),
-		Burn:     renderBurn(burn, cn, r),
-		SevenDay: renderSevenDay(data, pace, cn, r),
+		FiveHour: RenderFiveHour(st, cfg, r),
+		Burn:     RenderBurn(st, r),
+		SevenDay: RenderSevenDay(st, fc, cfg, r),
+	}
+}
+
+// RenderFiveHour produces the 5h section, or "5h: --" when data is unavailable.
+func RenderFiveHour(st State, cfg types.Config, r ports.Renderer) string {
+	if st.Err != nil {
+		return "5h: " + r.Dim("--")
+	}
+	return renderFiveHour(st.Data, st.Pace, st.Norm, cfg, r)
+}
+
+// RenderBurn produces the burn rate section, or "🔥 --" when data is unavailable.
+func RenderBurn(st State, r ports.Renderer) string {
+	if st.Err != nil {
+		return r.Icon(types.IconFlame) + " " + r.Dim("--")
+	}
+	return renderBurn(st.Burn, st.Norm, r)
+}
+
+// RenderSevenDay produces the 7d section with the weekly forecast fc,
+// or "7d: --" when data is unavailable.
+func RenderSevenDay(st State, fc history.Forecast, cfg types.Config, r ports.Renderer) string {
+	if st.Err != nil {
+		return "7d: " + r.Dim("--")
+	}
+	return renderSevenDay(st.Data, st.SevenDay(), st.Pace, fc, st.Norm, cfg, r)
+}
+
+// RenderFiveHourCompact produces the 5h section without bar, pace or reset info: "5h: 46%".
+// The limit warning is kept since it is the most actionable part.
+func RenderFiveHourCompact(st State, cfg types.Config, r ports.Renderer) string {
+	if st.Err != nil {
+		return "5h: " + r.Dim("--")
+	}
+	fivePct := int(math.Round(st.Data.FiveHourPercent))
+	display := "5h: " + r.Style(fmt.Sprintf("%d%%", fivePct), cfg.Thresholds.FiveHour.Role(float64(fivePct)))
+	if st.Pace.HittingLimit {
+		display += " " + r.Style("⚠️", types.RoleCritical)
 	}
+	return display
 }
 
-func renderFiveHour(data types.RateLimitData, pace types.PaceInfo, cn types.CostNorm, r ports.Renderer) string {
+// RenderBurnCompact produces the burn section without units or global rate: "🔥 5.0K".
+func RenderBurnCompact(st State, r ports.Renderer) string {
+	normalizedTPM := int(math.Round(st.Burn.LocalTPM * st.Norm.Mult))
+	if st.Err != nil || normalizedTPM <= 0 {
+		return r.Icon(types.IconFlame) + " " + r.Dim("--")
+	}
+	return r.Icon(types.IconFlame) + " " + r.Style(st.Norm.Prefix+r.FormatTokensF(normalizedTPM), types.RoleBurn)
+}
+
+// RenderSevenDayCompact produces the 7d section without bar, pace, reset info
+// or other weekly windows: "7d: 27%" (or "7d-opus: 62%").
+func RenderSevenDayCompact(st State, cfg types.Config, r ports.Renderer) string {
+	if st.Err != nil {
+		return "7d: " + r.Dim("--")
+	}
+	weekly := st.SevenDay()
+	sevenPct := int(math.Round(weekly.Percent))
+	return weekly.Name + ": " + r.Style(fmt.Sprintf("%d%%", sevenPct), cfg.Thresholds.SevenDay.Role(float64(sevenPct)))
+}
+
+// RenderExtra produces the extra usage section: the paid spend beyond the
+// limits versus the monthly limit, "extra $12.34/$50.00", colored by the extra
+// threshold. It is empty when extra usage is off or unknown.
+func RenderExtra(st State, cfg types.Config, r ports.Renderer) string {
+	extra := st.Data.Extra
+	if st.Err != nil || !extra.Enabled {
+		return ""
+	}
+	text := r.Dim("extra ") + RenderExtraCompact(st, cfg, r)
+	if extra.Limit > 0 {
+		text += r.Dim("/" + r.FormatCost(extra.Limit))
+	}
+	return text
+}
+
+// RenderExtraCompact produces the extra usage section as the spend only: "$12.34".
+func RenderExtraCompact(st State, cfg types.Config, r ports.Renderer) string {
+	extra := st.Data.Extra
+	if st.Err != nil || !extra.Enabled {
+		return ""
+	}
+	return r.Style(r.FormatCost(extra.Used), cfg.Thresholds.Extra.Role(extra.Percent()))
+}
+
+func renderFiveHour(data types.RateLimitData, pace types.PaceInfo, cn types.CostNorm, cfg types.Config, r ports.Renderer) string {
 	fivePct := int(math.Round(data.FiveHourPercent))
-	bar := r.MakeSplitBar(fivePct, pace.FiveHourTimePct, 8)
-	rateDisplay := r.Colorize(fmt.Sprintf("%d%%", fivePct), fivePct)
+	bs := cfg.FiveHourBar
+	bs.Levels = cfg.Thresholds.FiveHour
+	bar := r.MakeSplitBar(fivePct, pace.FiveHourTimePct, bs)
+	rateDisplay := r.Style(fmt.Sprintf("%d%%", fivePct), cfg.Thresholds.FiveHour.Role(float64(fivePct)))
 
 	// Pace (cost-normalized)
 	if pace.FiveHourPace > 0 {
 		normalizedPace := pace.FiveHourPace * cn.Mult
-		rateDisplay += " " + paceColorize(normalizedPace, cn.Prefix, r)
+		rateDisplay += " " + paceColorize(normalizedPace, cn.Prefix, cfg, r)
 	}
 
 	// Hitting limit warning with ETA (raw capacity, not cost-normalized)
 	if pace.HittingLimit {
 		if pace.LimitETA != "" {
-			rateDisplay += " " + r.Color("⚠️ ~"+pace.LimitETA, render.Red)
+			rateDisplay += " " + r.Style("⚠️ ~"+pace.LimitETA, types.RoleCritical)
 		} else {
-			rateDisplay += " " + r.Color("⚠️", render.Red)
+			rateDisplay += " " + r.Style("⚠️", types.RoleCritical)
 		}
 	}
 
-	// Reset time info (already contains →, cyan coloring)
-	if pace.ResetInfo != "" {
-		rateDisplay += " " + pace.ResetInfo
+	// Reset countdown, plus local reset time when close
+	if pace.ResetIn != "" {
+		rateDisplay += " " + r.Dim("→") + r.Style(pace.ResetIn, types.RoleResetTime)
+		if pace.ResetAt != "" {
+			rateDisplay += " " + r.Dim("@") + r.Style(pace.ResetAt, types.RoleResetTime)
+		}
 	}
 
 	return fmt.Sprintf("5h: %s %s", bar, rateDisplay)
@@ -177,12 +435,12 @@ func renderBurn(burn types.BurnInfo, cn types.CostNorm, r ports.Renderer) string
 
 	if normalizedTPM > 0 {
 		tpmStr := cn.Prefix + r.FormatTokensF(normalizedTPM)
-		display := fmt.Sprintf("🔥 %s", r.Color(tpmStr, render.Magenta))
+		display := r.Icon(types.IconFlame) + " " + r.Style(tpmStr, types.RoleBurn)
 
 		// Show global total when other sessions/subagents are active
 		if burn.IsHighActivity && normalizedGlobal > normalizedTPM {
 			globalStr := cn.Prefix + r.FormatTokensF(normalizedGlobal)
-			display += r.Dim("/") + r.Color(globalStr, render.Magenta)
+			display += r.Dim("/") + r.Style(globalStr, types.RoleBurn)
 		}
 
 		display += " " + r.Dim("t/m")
@@ -192,34 +450,55 @@ func renderBurn(burn types.BurnInfo, cn types.CostNorm, r ports.Renderer) string
 	// No local activity but others are active — show only global
 	if burn.IsHighActivity && normalizedGlobal > 0 {
 		globalStr := cn.Prefix + r.FormatTokensF(normalizedGlobal)
-		return fmt.Sprintf("🔥 %s %s", r.Dim("--")+r.Dim("/")+r.Color(globalStr, render.Magenta), r.Dim("t/m"))
+		return fmt.Sprintf("%s %s %s", r.Icon(types.IconFlame), r.Dim("--")+r.Dim("/")+r.Style(globalStr, types.RoleBurn), r.Dim("t/m"))
 	}
 
-	return "🔥 " + r.Dim("--")
+	return r.Icon(types.IconFlame) + " " + r.Dim("--")
 }
 
-func renderSevenDay(data types.RateLimitData, pace types.PaceInfo, cn types.CostNorm, r ports.Renderer) string {
-	sevenPct := int(math.Round(data.SevenDayPercent))
-	bar := r.MakeSplitBar(sevenPct, pace.SevenDayTimePct, 8)
-	display := r.Colorize(fmt.Sprintf("%d%%", sevenPct), sevenPct)
+func renderSevenDay(data types.RateLimitData, weekly types.RateWindow, pace types.PaceInfo, fc history.Forecast, cn types.CostNorm, cfg types.Config, r ports.Renderer) string {
+	sevenPct := int(math.Round(weekly.Percent))
+	bs := cfg.SevenDayBar
+	bs.Levels = cfg.Thresholds.SevenDay
+	bar := r.MakeSplitBar(sevenPct, pace.SevenDayTimePct, bs)
+	display := r.Style(fmt.Sprintf("%d%%", sevenPct), cfg.Thresholds.SevenDay.Role(float64(sevenPct)))
 
 	// Pace (cost-normalized)
 	if pace.SevenDayPace > 0 {
 		normalizedPace := pace.SevenDayPace * cn.Mult
-		display += " " + paceColorize(normalizedPace, cn.Prefix, r)
+		display += " " + paceColorize(normalizedPace, cn.Prefix, cfg, r)
 	}
 
-	// 7-day warning (cost-normalized budget sustainability)
-	if pace.SevenDayPace*cn.Mult > 1.0 {
-		display += " " + r.Color("⚠️", render.Red)
+	// 7-day warning: the projected exhaustion ("~" when uncertain), else
+	// the cost-normalized budget sustainability
+	switch {
+	case !fc.ExhaustAt.IsZero():
+		eta := cfg.Locale.DayTime(fc.ExhaustAt)
+		if fc.Confidence < history.ConfidenceMedium {
+			eta = "~" + eta
+		}
+		display += " " + r.Style("⚠️ "+eta, types.RoleCritical)
+	case fc.Confidence >= history.ConfidenceMedium:
+		// A reliable forecast says the limit lasts until the reset
+	case pace.SevenDayPace*cn.Mult > 1.0:
+		display += " " + r.Style("⚠️", types.RoleCritical)
 	}
 
 	// Days left (only ≤3 days)
-	if pace.SevenDayResetFmt != "" {
-		display += " " + pace.SevenDayResetFmt
+	if pace.SevenDayResetIn != "" {
+		display += " " + r.Dim("→") + r.Style(pace.SevenDayResetIn, types.RoleResetTime)
 	}
 
-	return fmt.Sprintf("7d: %s %s", bar, display)
+	// Other weekly windows in use, e.g. "· 7d-opus 62%"
+	for _, w := range data.Windows {
+		pct := int(math.Round(w.Percent))
+		if w.Name == weekly.Name || !strings.HasPrefix(w.Name, types.WindowSevenDay) || pct <= 0 {
+			continue
+		}
+		display += " " + r.Dim("· "+w.Name+" ") + r.Style(fmt.Sprintf("%d%%", pct), cfg.Thresholds.SevenDay.Role(float64(pct)))
+	}
+
+	return fmt.Sprintf("%s: %s %s", weekly.Name, bar, display)
 }
 
 // Render produces the full rate limit string (legacy, for empty stdin fallback).
@@ -229,17 +508,10 @@ func Render(creds types.Credentials, cfg types.Config, plat ports.PlatformInfo,
 		return ""
 	}
 	fivePct := int(math.Round(data.FiveHourPercent))
-	return fmt.Sprintf("5h: %s", r.Colorize(fmt.Sprintf("%d%%", fivePct), fivePct))
+	return fmt.Sprintf("5h: %s", r.Style(fmt.Sprintf("%d%%", fivePct), cfg.Thresholds.FiveHour.Role(float64(fivePct))))
 }
 
-func paceColorize(pace float64, prefix string, r ports.Renderer) string {
-	text := fmt.Sprintf("%s%.1fx", prefix, pace)
-	switch {
-	case pace < 1.0:
-		return r.Color(text, render.Green)
-	case pace < 1.5:
-		return r.Color(text, render.Yellow)
-	default:
-		return r.Color(text, render.Red)
-	}
+func paceColorize(pace float64, prefix string, cfg types.Config, r ports.Renderer) string {
+	text := prefix + cfg.Locale.Number(pace, 1) + "x"
+	return r.Style(text, cfg.Thresholds.Pace.Role(pace))
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Benniphx/claude-statusline/core/history"
 	"github.com/Benniphx/claude-statusline/core/types"
 )
 
@@ -49,6 +50,11 @@ func (m *mockCacheStore) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+func (m *mockCacheStore) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+
 func (m *mockCacheStore) FileMTime(path string) (time.Time, error) {
 	return time.Now(), nil
 }
@@ -63,11 +69,13 @@ func (e *notFoundErr) Error() string { return "not found" }
 
 // mockAPIClient implements ports.APIClient for testing.
 type mockAPIClient struct {
-	resp *types.RateLimitResponse
-	err  error
+	resp  *types.RateLimitResponse
+	err   error
+	calls int
 }
 
 func (m *mockAPIClient) FetchRateLimits(token string) (*types.RateLimitResponse, error) {
+	m.calls++
 	return m.resp, m.err
 }
 
@@ -75,6 +83,9 @@ func (m *mockAPIClient) FetchLatestRelease(repo string) (string, error) {
 	return "v1.0.0", nil
 }
 
+// tokenCache is the cache file of the test credentials {OAuthToken: "token"}.
+var tokenCache = "/tmp/" + types.AccountFile(cacheName, types.Credentials{OAuthToken: "token"}.AccountKey())
+
 func TestLoadFromCache(t *testing.T) {
 	store := newMockCache()
 
@@ -83,7 +94,7 @@ func TestLoadFromCache(t *testing.T) {
 		SevenDay: types.RateLimitWindow{Utilization: 30, ResetsAt: "2025-02-10T00:00:00Z"},
 	}
 	raw, _ := json.Marshal(resp)
-	cachePath := "/tmp/" + cacheName
+	cachePath := tokenCache
 	store.files[cachePath] = raw
 	store.fresh[cachePath] = true
 
@@ -101,6 +112,12 @@ func TestLoadFromCache(t *testing.T) {
 	if data.SevenDayPercent != 30 {
 		t.Errorf("SevenDayPercent = %f, want 30", data.SevenDayPercent)
 	}
+	if data.Source != types.RateSourceCache || data.FromCache {
+		t.Errorf("Source = %q, FromCache = %v; want cache, false", data.Source, data.FromCache)
+	}
+	if data.FetchedAt.IsZero() {
+		t.Error("FetchedAt should be the cache file mtime")
+	}
 }
 
 // mockPlatform implements ports.PlatformInfo for ratelimit tests.
@@ -122,11 +139,10 @@ func (m *mockPlatform) GetStableSessionID() (string, bool) {
 // mockRenderer implements ports.Renderer for ratelimit render tests.
 type mockRenderer struct{}
 
-func (m *mockRenderer) Colorize(text string, percent int) string { return text }
-func (m *mockRenderer) Color(text, color string) string          { return text }
+func (m *mockRenderer) Style(text string, role types.Role) string         { return text }
 func (m *mockRenderer) Dim(text string) string                   { return text }
-func (m *mockRenderer) MakeBar(percent, width int) string                  { return "[bar]" }
-func (m *mockRenderer) MakeSplitBar(usagePct, timePct, width int) string   { return "[split]" }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                  { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string   { return "[split]" }
 func (m *mockRenderer) FormatTokens(n int) string {
 	if n >= 1000 {
 		return fmt.Sprintf("%.0fK", float64(n)/1000.0)
@@ -140,6 +156,7 @@ func (m *mockRenderer) FormatTokensF(n int) string {
 	return fmt.Sprintf("%d", n)
 }
 func (m *mockRenderer) FormatCost(f float64) string { return fmt.Sprintf("$%.2f", f) }
+func (m *mockRenderer) Icon(name types.Icon) string { return types.EmojiIcons[name] }
 
 func TestLoadFromAPI(t *testing.T) {
 	store := newMockCache()
@@ -161,14 +178,43 @@ func TestLoadFromAPI(t *testing.T) {
 	if data.FiveHourPercent != 72 {
 		t.Errorf("FiveHourPercent = %f, want 72", data.FiveHourPercent)
 	}
+	if data.Source != types.RateSourceAPI {
+		t.Errorf("Source = %q, want %q", data.Source, types.RateSourceAPI)
+	}
 
 	// Should have cached the response
-	cachePath := "/tmp/" + cacheName
+	cachePath := tokenCache
 	if _, ok := store.files[cachePath]; !ok {
 		t.Error("should have cached the API response")
 	}
 }
 
+func TestLoadPerAccountCache(t *testing.T) {
+	store := newMockCache()
+	cfg := types.DefaultConfig()
+	work := types.Credentials{OAuthToken: "work-token", AccountID: "work"}
+	personal := types.Credentials{OAuthToken: "personal-token", AccountID: "personal"}
+
+	api := &mockAPIClient{resp: &types.RateLimitResponse{FiveHour: types.RateLimitWindow{Utilization: 80}}}
+	if _, err := Load(work, cfg, store, api); err != nil {
+		t.Fatalf("Load(work): %v", err)
+	}
+	store.fresh[cacheFile(work, cfg)] = true
+
+	// Switching accounts must not serve the other account's cache
+	api.resp = &types.RateLimitResponse{FiveHour: types.RateLimitWindow{Utilization: 5}}
+	data, err := Load(personal, cfg, store, api)
+	if err != nil || data.FiveHourPercent != 5 || api.calls != 2 {
+		t.Errorf("Load(personal) = %v%% after %d calls (err %v), want a fresh 5%%", data.FiveHourPercent, api.calls, err)
+	}
+	if data, _ := Load(work, cfg, store, api); data.FiveHourPercent != 80 || data.Source != types.RateSourceCache {
+		t.Errorf("Load(work) = %v%% from %s, want the cached 80%%", data.FiveHourPercent, data.Source)
+	}
+	if _, err := LoadCached(types.Credentials{OAuthToken: "other", AccountID: "other"}, cfg, store); err == nil {
+		t.Error("LoadCached for an account without cache should fail")
+	}
+}
+
 func TestLoadNoOAuth(t *testing.T) {
 	store := newMockCache()
 	api := &mockAPIClient{}
@@ -189,7 +235,7 @@ func TestLoadStaleCacheFallback(t *testing.T) {
 		SevenDay: types.RateLimitWindow{Utilization: 20, ResetsAt: "2025-02-10T00:00:00Z"},
 	}
 	raw, _ := json.Marshal(resp)
-	cachePath := "/tmp/" + cacheName
+	cachePath := tokenCache
 	store.files[cachePath] = raw
 	store.fresh[cachePath] = false // stale
 
@@ -207,6 +253,158 @@ func TestLoadStaleCacheFallback(t *testing.T) {
 	if !data.FromCache {
 		t.Error("FromCache should be true for stale cache fallback")
 	}
+	if data.Source != types.RateSourceCache {
+		t.Errorf("Source = %q, want %q", data.Source, types.RateSourceCache)
+	}
+}
+
+func TestComputeGatesPollByVersion(t *testing.T) {
+	resp := &types.RateLimitResponse{
+		FiveHour: types.RateLimitWindow{Utilization: 72, ResetsAt: "2025-02-06T19:00:00Z"},
+		SevenDay: types.RateLimitWindow{Utilization: 45, ResetsAt: "2025-02-10T00:00:00Z"},
+	}
+	stale, _ := json.Marshal(types.RateLimitResponse{
+		FiveHour: types.RateLimitWindow{Utilization: 40, ResetsAt: "2025-02-06T19:00:00Z"},
+	})
+	tests := []struct {
+		name      string
+		version   string
+		cached    bool
+		fresh     bool
+		wantCalls int
+		wantPct   float64 // 0 = no data
+	}{
+		{"unknown version polls", "", false, false, 1, 72},
+		{"before native rate_limits polls", "2.1.79", false, false, 1, 72},
+		{"native rate_limits serves stale cache", "2.1.80", true, false, 0, 40},
+		{"native rate_limits serves fresh cache", "2.1.80", true, true, 0, 40},
+		{"native rate_limits without cache polls", "2.2.0", false, false, 1, 72},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockCache()
+			if tt.cached {
+				store.files[tokenCache] = stale
+				store.fresh[tokenCache] = tt.fresh
+			}
+			api := &mockAPIClient{resp: resp}
+			input := types.Input{Version: tt.version} // no stdin rate_limits
+			st := Compute(input, types.Credentials{OAuthToken: "token"}, types.DefaultConfig(), &mockPlatform{}, store, api, types.ModelInfo{})
+
+			if api.calls != tt.wantCalls {
+				t.Errorf("API calls = %d, want %d", api.calls, tt.wantCalls)
+			}
+			if tt.wantPct == 0 {
+				if st.Err == nil {
+					t.Error("want an error without rate limit data")
+				}
+				return
+			}
+			if st.Err != nil || st.Data.FiveHourPercent != tt.wantPct {
+				t.Errorf("FiveHourPercent = %v (err %v), want %v", st.Data.FiveHourPercent, st.Err, tt.wantPct)
+			}
+			if st.Data.FromCache != tt.cached || (st.Data.Source == types.RateSourceCache) != tt.cached {
+				t.Errorf("FromCache = %v from %s, want cached %v", st.Data.FromCache, st.Data.Source, tt.cached)
+			}
+		})
+	}
+}
+
+func TestComputeCachesStdin(t *testing.T) {
+	store := newMockCache()
+	api := &mockAPIClient{err: fmt.Errorf("offline")}
+	creds := types.Credentials{OAuthToken: "token"}
+	cfg := types.DefaultConfig()
+
+	input := types.Input{Version: "2.1.80", RateLimits: &types.StdinRateLimits{
+		FiveHour: types.StdinRateWindow{UsedPercentage: 46, ResetsAt: "2026-03-20T18:00:00Z"},
+		SevenDay: types.StdinRateWindow{UsedPercentage: 27, ResetsAt: "2026-03-24T00:00:00Z"},
+	}}
+	if st := Compute(input, creds, cfg, &mockPlatform{}, store, api, types.ModelInfo{}); st.Err != nil {
+		t.Fatalf("Compute with stdin: %v", st.Err)
+	}
+	if _, ok := store.files[tokenCache]; !ok {
+		t.Fatal("stdin rate_limits should be cached")
+	}
+
+	// A new session without rate_limits yet shows the cached stdin data
+	st := Compute(types.Input{Version: "2.1.80"}, creds, cfg, &mockPlatform{}, store, api, types.ModelInfo{})
+	if st.Err != nil || st.Data.FiveHourPercent != 46 || st.Data.SevenDayPercent != 27 || api.calls != 0 {
+		t.Errorf("new session = 5h %v%%, 7d %v%% after %d API calls (err %v), want cached 46%%/27%% without polling",
+			st.Data.FiveHourPercent, st.Data.SevenDayPercent, api.calls, st.Err)
+	}
+}
+
+func TestHistoryLoadedApart(t *testing.T) {
+	store := newMockCache()
+	creds := types.Credentials{OAuthToken: "token"}
+	cfg := types.DefaultConfig()
+	historyFile := "/tmp/" + types.AccountFile("claude_rate_history.txt", creds.AccountKey())
+	input := types.Input{Version: "2.1.80", RateLimits: &types.StdinRateLimits{
+		FiveHour: types.StdinRateWindow{UsedPercentage: 46, ResetsAt: "2026-03-20T18:00:00Z"},
+		SevenDay: types.StdinRateWindow{UsedPercentage: 27, ResetsAt: "2026-03-24T00:00:00Z"},
+	}}
+
+	st := Compute(input, creds, cfg, &mockPlatform{}, store, &mockAPIClient{}, types.ModelInfo{})
+	if _, ok := store.files[historyFile]; ok {
+		t.Fatal("Compute should leave the history to the sections showing it")
+	}
+
+	now := time.Now()
+	if points := History(st, creds, cfg, store, now); len(points) != 1 || points[0].SevenDay != 27 {
+		t.Errorf("History of live data = %+v, want the current point recorded", points)
+	}
+	cached := st
+	cached.Data.Source = types.RateSourceCache
+	if points := History(cached, creds, cfg, store, now.Add(time.Hour)); len(points) != 1 {
+		t.Errorf("History of cached data = %+v, want only the recorded point", points)
+	}
+	if points := History(State{Err: fmt.Errorf("offline")}, creds, cfg, store, now); points != nil {
+		t.Errorf("History without data = %+v, want none", points)
+	}
+
+	// The history tracks the shared 7d window only
+	opus := st
+	opus.Weekly = types.RateWindow{Name: "7d-opus", Percent: 62}
+	if fc := Forecast(opus, History(st, creds, cfg, store, now), now); fc != (history.Forecast{}) {
+		t.Errorf("Forecast with 7d-opus driving 7d = %+v, want none", fc)
+	}
+}
+
+func TestComputeModelWeeklyWindow(t *testing.T) {
+	store := newMockCache()
+	raw := `{"five_hour": {"utilization": 10, "resets_at": "2025-02-06T19:00:00Z"},
+		"seven_day": {"utilization": 45, "resets_at": "2025-02-10T00:00:00Z"},
+		"seven_day_opus": {"utilization": 62, "resets_at": "2025-02-10T00:00:00Z"}}`
+	store.files[tokenCache] = []byte(raw)
+	store.fresh[tokenCache] = true
+	r := &mockRenderer{}
+	cfg := types.DefaultConfig()
+
+	// The reset is past: the whole week elapsed, pace = usage / 100%
+	tests := []struct {
+		family      string
+		want        string
+		wantCompact string
+	}{
+		{"opus", "7d-opus: [split] 62% 0.6x →<1d · 7d 45%", "7d-opus: 62%"},
+		{"sonnet", "7d: [split] 45% 0.5x →<1d · 7d-opus 62%", "7d: 45%"},
+	}
+	for _, tt := range tests {
+		st := Compute(types.Input{}, types.Credentials{OAuthToken: "token"}, cfg, &mockPlatform{}, store, &mockAPIClient{}, types.ModelInfo{Family: tt.family})
+		if st.Err != nil {
+			t.Fatal(st.Err)
+		}
+		if len(st.Data.Windows) != 3 {
+			t.Errorf("Windows = %+v, want 5h, 7d and 7d-opus", st.Data.Windows)
+		}
+		if got := RenderSevenDay(st, history.Forecast{}, cfg, r); got != tt.want {
+			t.Errorf("%s: 7d = %q, want %q", tt.family, got, tt.want)
+		}
+		if got := RenderSevenDayCompact(st, cfg, r); got != tt.wantCompact {
+			t.Errorf("%s: compact 7d = %q, want %q", tt.family, got, tt.wantCompact)
+		}
+	}
 }
 
 func TestRenderSectionsSuccess(t *testing.T) {
@@ -226,7 +424,7 @@ func TestRenderSectionsSuccess(t *testing.T) {
 		},
 	}
 	raw, _ := json.Marshal(resp)
-	cachePath := "/tmp/" + cacheName
+	cachePath := tokenCache
 	store.files[cachePath] = raw
 	store.fresh[cachePath] = true
 
@@ -315,8 +513,8 @@ func TestRenderSectionsNoBurn(t *testing.T) {
 		},
 	}
 	raw, _ := json.Marshal(resp)
-	store.files["/tmp/"+cacheName] = raw
-	store.fresh["/tmp/"+cacheName] = true
+	store.files[tokenCache] = raw
+	store.fresh[tokenCache] = true
 
 	creds := types.Credentials{OAuthToken: "token"}
 	cfg := types.DefaultConfig()
@@ -357,8 +555,8 @@ func TestRenderLegacyFallback(t *testing.T) {
 		},
 	}
 	raw, _ := json.Marshal(resp)
-	store.files["/tmp/"+cacheName] = raw
-	store.fresh["/tmp/"+cacheName] = true
+	store.files[tokenCache] = raw
+	store.fresh[tokenCache] = true
 
 	creds := types.Credentials{OAuthToken: "token"}
 	cfg := types.DefaultConfig()
@@ -402,7 +600,7 @@ func TestPaceColorize(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := paceColorize(tt.pace, "", r)
+		got := paceColorize(tt.pace, "", types.DefaultConfig(), r)
 		if got != tt.want {
 			t.Errorf("paceColorize(%f) = %q, want %q", tt.pace, got, tt.want)
 		}
@@ -420,7 +618,7 @@ func TestRenderFiveHourWithPace(t *testing.T) {
 		HittingLimit: false,
 	}
 
-	result := renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, r)
+	result := renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
 	if !strings.Contains(result, "50%") {
 		t.Errorf("should contain '50%%', got: %s", result)
 	}
@@ -441,7 +639,7 @@ func TestRenderFiveHourHittingLimit(t *testing.T) {
 		LimitETA:     "14:30",
 	}
 
-	result := renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, r)
+	result := renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
 	if !strings.Contains(result, "⚠️") {
 		t.Errorf("should contain ⚠️ when hitting limit, got: %s", result)
 	}
@@ -450,6 +648,57 @@ func TestRenderFiveHourHittingLimit(t *testing.T) {
 	}
 }
 
+func TestRenderFiveHourWithReset(t *testing.T) {
+	r := &mockRenderer{}
+
+	data := types.RateLimitData{
+		FiveHourPercent: 40.0,
+	}
+	pace := types.PaceInfo{
+		ResetIn: "25m",
+		ResetAt: "14:30",
+	}
+
+	result := renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
+	if !strings.HasSuffix(result, "→25m @14:30") {
+		t.Errorf("should end with '→25m @14:30', got: %s", result)
+	}
+
+	pace.ResetAt = ""
+	result = renderFiveHour(data, pace, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
+	if !strings.HasSuffix(result, "→25m") {
+		t.Errorf("should end with '→25m', got: %s", result)
+	}
+}
+
+func TestRenderCompactSections(t *testing.T) {
+	r := &mockRenderer{}
+
+	st := State{
+		Data: types.RateLimitData{FiveHourPercent: 90.4, SevenDayPercent: 27.0},
+		Pace: types.PaceInfo{FiveHourPace: 3.0, HittingLimit: true, LimitETA: "14:30", SevenDayPace: 0.3},
+		Burn: types.BurnInfo{LocalTPM: 5000},
+		Norm: types.CostNorm{Mult: 1.0},
+	}
+
+	if got, want := RenderFiveHourCompact(st, types.DefaultConfig(), r), "5h: 90% ⚠️"; got != want {
+		t.Errorf("RenderFiveHourCompact = %q, want %q", got, want)
+	}
+	if got, want := RenderSevenDayCompact(st, types.DefaultConfig(), r), "7d: 27%"; got != want {
+		t.Errorf("RenderSevenDayCompact = %q, want %q", got, want)
+	}
+	if got, want := RenderBurnCompact(st, r), "🔥 5.0K"; got != want {
+		t.Errorf("RenderBurnCompact = %q, want %q", got, want)
+	}
+
+	failed := State{Err: fmt.Errorf("no data")}
+	for _, got := range []string{RenderFiveHourCompact(failed, types.DefaultConfig(), r), RenderBurnCompact(failed, r), RenderSevenDayCompact(failed, types.DefaultConfig(), r)} {
+		if !strings.HasSuffix(got, "--") {
+			t.Errorf("compact render without data = %q, want '--' placeholder", got)
+		}
+	}
+}
+
 func TestLoadFromStdin(t *testing.T) {
 	now := time.Now()
 	resetAt := now.Add(3 * time.Hour).Format(time.RFC3339)
@@ -470,6 +719,9 @@ func TestLoadFromStdin(t *testing.T) {
 	if data.SevenDayPercent != 18 {
 		t.Errorf("SevenDayPercent = %f, want 18", data.SevenDayPercent)
 	}
+	if data.Source != types.RateSourceStdin {
+		t.Errorf("Source = %q, want %q", data.Source, types.RateSourceStdin)
+	}
 }
 
 func TestLoadFromStdinNil(t *testing.T) {
@@ -499,6 +751,65 @@ func TestLoadFromStdinMalformedTimestamp(t *testing.T) {
 	}
 }
 
+func TestExtraUsage(t *testing.T) {
+	cents := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name string
+		resp *types.ExtraUsageResponse
+		want types.ExtraUsage
+	}{
+		{"missing", nil, types.ExtraUsage{}},
+		{"disabled", &types.ExtraUsageResponse{IsEnabled: false, UsedCredits: cents(500)}, types.ExtraUsage{}},
+		{"limited", &types.ExtraUsageResponse{IsEnabled: true, MonthlyLimit: cents(5000), UsedCredits: cents(1234)}, types.ExtraUsage{Enabled: true, Used: 12.34, Limit: 50}},
+		{"unlimited", &types.ExtraUsageResponse{IsEnabled: true, UsedCredits: cents(250)}, types.ExtraUsage{Enabled: true, Used: 2.5}},
+		{"utilization only", &types.ExtraUsageResponse{IsEnabled: true, MonthlyLimit: cents(2000), Utilization: cents(25)}, types.ExtraUsage{Enabled: true, Used: 5, Limit: 20}},
+	}
+	for _, tt := range tests {
+		if got := extraUsage(tt.resp); got != tt.want {
+			t.Errorf("%s: extraUsage = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	// Stdin carries the same object
+	limit, used := 1000.0, 900.0
+	data, err := LoadFromStdin(&types.StdinRateLimits{
+		FiveHour:   types.StdinRateWindow{UsedPercentage: 100, ResetsAt: "2026-03-20T18:00:00Z"},
+		SevenDay:   types.StdinRateWindow{UsedPercentage: 60, ResetsAt: "2026-03-24T00:00:00Z"},
+		ExtraUsage: &types.ExtraUsageResponse{IsEnabled: true, MonthlyLimit: &limit, UsedCredits: &used},
+	})
+	if err != nil {
+		t.Fatalf("LoadFromStdin: %v", err)
+	}
+	if want := (types.ExtraUsage{Enabled: true, Used: 9, Limit: 10}); data.Extra != want {
+		t.Errorf("stdin Extra = %+v, want %+v", data.Extra, want)
+	}
+}
+
+func TestRenderExtra(t *testing.T) {
+	r := &mockRenderer{}
+	cfg := types.DefaultConfig()
+
+	tests := []struct {
+		name        string
+		st          State
+		want        string
+		wantCompact string
+	}{
+		{"off", State{}, "", ""},
+		{"limited", State{Data: types.RateLimitData{Extra: types.ExtraUsage{Enabled: true, Used: 12.34, Limit: 50}}}, "extra $12.34/$50.00", "$12.34"},
+		{"unlimited", State{Data: types.RateLimitData{Extra: types.ExtraUsage{Enabled: true, Used: 2.5}}}, "extra $2.50", "$2.50"},
+		{"no data", State{Err: fmt.Errorf("no data"), Data: types.RateLimitData{Extra: types.ExtraUsage{Enabled: true}}}, "", ""},
+	}
+	for _, tt := range tests {
+		if got := RenderExtra(tt.st, cfg, r); got != tt.want {
+			t.Errorf("%s: RenderExtra = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := RenderExtraCompact(tt.st, cfg, r); got != tt.wantCompact {
+			t.Errorf("%s: RenderExtraCompact = %q, want %q", tt.name, got, tt.wantCompact)
+		}
+	}
+}
+
 func TestRenderBurnWithTPM(t *testing.T) {
 	r := &mockRenderer{}
 
@@ -557,7 +868,7 @@ func TestRenderSevenDayWithPace(t *testing.T) {
 		SevenDayPace: 0.8,
 	}
 
-	result := renderSevenDay(data, pace, types.CostNorm{Mult: 1.0}, r)
+	result := renderSevenDay(data, data.Weekly(""), pace, history.Forecast{}, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
 	if !strings.Contains(result, "25%") {
 		t.Errorf("should contain '25%%', got: %s", result)
 	}
@@ -576,12 +887,45 @@ func TestRenderSevenDayOverPace(t *testing.T) {
 		SevenDayPace: 1.5,
 	}
 
-	result := renderSevenDay(data, pace, types.CostNorm{Mult: 1.0}, r)
+	result := renderSevenDay(data, data.Weekly(""), pace, history.Forecast{}, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
 	if !strings.Contains(result, "⚠️") {
 		t.Errorf("should contain ⚠️ when 7d pace > 1.0, got: %s", result)
 	}
 }
 
+func TestRenderSevenDayForecast(t *testing.T) {
+	r := &mockRenderer{}
+	data := types.RateLimitData{SevenDayPercent: 60.0}
+	exhaust := time.Date(2026, 3, 5, 15, 0, 0, 0, time.Local) // a Thursday
+	cfg := types.DefaultConfig()
+
+	tests := []struct {
+		name string
+		pace float64
+		fc   history.Forecast
+		want string // "" = no warning
+	}{
+		{"projected exhaustion", 0.8, history.Forecast{ExhaustAt: exhaust, Confidence: history.ConfidenceHigh}, "⚠️ Thu 15:00"},
+		{"uncertain exhaustion", 0.8, history.Forecast{ExhaustAt: exhaust, Confidence: history.ConfidenceLow}, "⚠️ ~Thu 15:00"},
+		{"reliable forecast overrides pace", 1.5, history.Forecast{Confidence: history.ConfidenceMedium}, ""},
+		{"uncertain forecast keeps pace warning", 1.5, history.Forecast{Confidence: history.ConfidenceLow}, "⚠️"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := renderSevenDay(data, data.Weekly(""), types.PaceInfo{SevenDayPace: tt.pace}, tt.fc, types.CostNorm{Mult: 1.0}, cfg, r)
+			if tt.want == "" {
+				if strings.Contains(result, "⚠️") {
+					t.Errorf("want no warning, got: %s", result)
+				}
+				return
+			}
+			if !strings.HasSuffix(result, " "+tt.want) {
+				t.Errorf("want %q, got: %s", tt.want, result)
+			}
+		})
+	}
+}
+
 func TestRenderSevenDayWithResetDays(t *testing.T) {
 	r := &mockRenderer{}
 
@@ -589,11 +933,11 @@ func TestRenderSevenDayWithResetDays(t *testing.T) {
 		SevenDayPercent: 30.0,
 	}
 	pace := types.PaceInfo{
-		SevenDayPace:     0.5,
-		SevenDayResetFmt: "→2d",
+		SevenDayPace:    0.5,
+		SevenDayResetIn: "2d",
 	}
 
-	result := renderSevenDay(data, pace, types.CostNorm{Mult: 1.0}, r)
+	result := renderSevenDay(data, data.Weekly(""), pace, history.Forecast{}, types.CostNorm{Mult: 1.0}, types.DefaultConfig(), r)
 	if !strings.Contains(result, "→2d") {
 		t.Errorf("should contain '→2d', got: %s", result)
 	}
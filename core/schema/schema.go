@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+const (
+	logFile    = "claude_statusline_stdin.log"
+	logMaxSize = 64 * 1024 // Rotated to <log>.1 beyond this size
+)
+
+// Report lists how a stdin payload differs from the schema of types.Input.
+// Keys are dotted paths, e.g. "workspace.current_dir".
+type Report struct {
+	Unknown []string // Keys the statusline does not read
+	Missing []string // Expected keys absent from the payload (omitempty fields are optional)
+	Invalid []string // Keys whose value has an unexpected JSON type: "cost.total_cost_usd (string)"
+}
+
+// Empty reports whether the payload matches the schema.
+func (r Report) Empty() bool {
+	return len(r.Unknown) == 0 && len(r.Missing) == 0 && len(r.Invalid) == 0
+}
+
+// String summarizes the report on one line: "unknown=a,b missing=c".
+func (r Report) String() string {
+	var parts []string
+	for _, p := range []struct {
+		name string
+		keys []string
+	}{{"unknown", r.Unknown}, {"missing", r.Missing}, {"invalid", r.Invalid}} {
+		if len(p.keys) > 0 {
+			parts = append(parts, p.name+"="+strings.Join(p.keys, ","))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Check compares a stdin payload with the schema of types.Input.
+func Check(raw []byte) (Report, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return Report{}, err
+	}
+	var r Report
+	r.walk("", obj, reflect.TypeOf(types.Input{}))
+	sort.Strings(r.Unknown)
+	sort.Strings(r.Missing)
+	sort.Strings(r.Invalid)
+	return r, nil
+}
+
+// walk compares the keys of obj with the JSON fields of struct type t.
+func (r *Report) walk(prefix string, obj map[string]json.RawMessage, t reflect.Type) {
+	known := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, optional := jsonName(f)
+		if name == "" {
+			continue
+		}
+		known[name] = true
+		path := prefix + name
+
+		value, ok := obj[name]
+		if !ok {
+			if !optional {
+				r.Missing = append(r.Missing, path)
+			}
+			continue
+		}
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+			var nested map[string]json.RawMessage
+			if json.Unmarshal(value, &nested) != nil {
+				r.Invalid = append(r.Invalid, fmt.Sprintf("%s (%s)", path, kind(value)))
+				continue
+			}
+			r.walk(path+".", nested, ft)
+			continue
+		}
+		if json.Unmarshal(value, reflect.New(ft).Interface()) != nil {
+			r.Invalid = append(r.Invalid, fmt.Sprintf("%s (%s)", path, kind(value)))
+		}
+	}
+	for name := range obj {
+		if !known[name] {
+			r.Unknown = append(r.Unknown, prefix+name)
+		}
+	}
+}
+
+// jsonName returns the JSON key of a struct field and whether it is omitempty.
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" || !f.IsExported() {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+// kind names the JSON type of a value.
+func kind(value json.RawMessage) string {
+	switch v := bytes.TrimSpace(value); {
+	case len(v) == 0:
+		return "empty"
+	case v[0] == '{':
+		return "object"
+	case v[0] == '[':
+		return "array"
+	case v[0] == '"':
+		return "string"
+	case v[0] == 't' || v[0] == 'f':
+		return "bool"
+	default:
+		return "number"
+	}
+}
+
+// Log appends a drift entry to the stdin log in the cache dir:
+// "<time> version=<v> unknown=... missing=...", or "invalid JSON: <err>".
+// Entries identical to the previous one are skipped, so an unchanged drift is
+// logged once rather than on every render. Entries are appended with a single
+// write, so tabs rendering at once don't overwrite each other; beyond
+// logMaxSize the log is rotated to <log>.1 and restarted atomically.
+func Log(raw []byte, cfg types.Config, store ports.CacheStore, now time.Time) {
+	var entry string
+	report, err := Check(raw)
+	switch {
+	case err != nil:
+		entry = "invalid JSON: " + err.Error()
+	case report.Empty():
+		return
+	default:
+		entry = fmt.Sprintf("version=%s %s", payloadVersion(raw), report)
+	}
+
+	path := fmt.Sprintf("%s/%s", cfg.CacheDir, logFile)
+	data, _ := store.ReadFile(path)
+	if lastEntry(data) == entry {
+		return
+	}
+	line := now.UTC().Format(time.RFC3339) + " " + entry + "\n"
+	if len(data) > logMaxSize {
+		store.AtomicWrite(path+".1", data)
+		store.AtomicWrite(path, []byte(line))
+		return
+	}
+	store.AppendFile(path, []byte(line))
+}
+
+// lastEntry returns the last log line without its timestamp.
+func lastEntry(data []byte) string {
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	_, entry, _ := strings.Cut(lines[len(lines)-1], " ")
+	return entry
+}
+
+// payloadVersion returns the Claude Code version of a payload, or "unknown".
+func payloadVersion(raw []byte) string {
+	var v struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(raw, &v) != nil || v.Version == "" {
+		return "unknown"
+	}
+	return v.Version
+}
@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockCache implements ports.CacheStore for testing.
+type mockCache struct {
+	files    map[string][]byte
+	rewrites int // WriteFile calls
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{files: make(map[string][]byte)}
+}
+
+func (m *mockCache) AtomicWrite(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) { return nil, false }
+func (m *mockCache) ReadFile(path string) ([]byte, error) {
+	data, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+func (m *mockCache) WriteFile(path string, data []byte) error {
+	m.rewrites++
+	m.files[path] = append([]byte(nil), data...)
+	return nil
+}
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+func (m *mockCache) FileMTime(path string) (time.Time, error) { return time.Now(), nil }
+func (m *mockCache) CleanOld(dir, pattern, keep string) error { return nil }
+
+// fullPayload sets every key of types.Input.
+const fullPayload = `{
+	"hook_event_name": "Status", "session_id": "abc", "transcript_path": "/t.jsonl", "cwd": "/src",
+	"workspace": {"current_dir": "/src", "project_dir": "/src"},
+	"version": "2.1.80", "output_style": {"name": "default"}, "exceeds_200k_tokens": false,
+	"context_window": {
+		"context_window_size": 200000, "used_percentage": 12.5,
+		"current_usage": {"input_tokens": 1, "cache_creation_input_tokens": 2, "cache_read_input_tokens": 3},
+		"total_input_tokens": 10, "total_output_tokens": 5
+	},
+	"model": {"id": "claude-opus-4-6", "display_name": "Opus"},
+	"cost": {"total_cost_usd": 0.5, "total_duration_ms": 1, "total_api_duration_ms": 1, "total_lines_added": 0, "total_lines_removed": 0},
+	"rate_limits": {"five_hour": {"used_percentage": 10, "resets_at": "x"}, "seven_day": {"used_percentage": 5, "resets_at": "y"}}
+}`
+
+func TestCheck(t *testing.T) {
+	r, err := Check([]byte(fullPayload))
+	if err != nil || !r.Empty() {
+		t.Fatalf("full payload: %+v, %v", r, err)
+	}
+
+	// Drift: renamed, added and retyped keys; optional keys may be absent
+	drifted := strings.NewReplacer(
+		`"display_name"`, `"name"`,
+		`"cwd": "/src",`, `"cwd": "/src", "agent": {"name": "x"},`,
+		`"context_window_size": 200000`, `"context_window_size": "200000"`,
+		`"used_percentage": 12.5,`, ``,
+	).Replace(fullPayload)
+	r, err = Check([]byte(drifted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Report{
+		Unknown: []string{"agent", "model.name"},
+		Missing: []string{"model.display_name"},
+		Invalid: []string{"context_window.context_window_size (string)"},
+	}
+	if r.String() != want.String() {
+		t.Errorf("Check() = %q, want %q", r, want)
+	}
+
+	// null counts as present: current_usage is null before the first response
+	r, _ = Check([]byte(strings.Replace(fullPayload, `{"input_tokens": 1, "cache_creation_input_tokens": 2, "cache_read_input_tokens": 3}`, "null", 1)))
+	if !r.Empty() {
+		t.Errorf("null current_usage: %+v", r)
+	}
+
+	if _, err := Check([]byte("not json")); err == nil {
+		t.Error("invalid JSON should return an error")
+	}
+}
+
+func TestLog(t *testing.T) {
+	cfg := types.DefaultConfig()
+	cfg.CacheDir = "/cache"
+	store := newMockCache()
+	now := time.Date(2026, 3, 5, 14, 30, 0, 0, time.UTC)
+	path := "/cache/" + logFile
+
+	Log([]byte(fullPayload), cfg, store, now)
+	if _, ok := store.files[path]; ok {
+		t.Error("matching payload should not be logged")
+	}
+
+	drifted := []byte(strings.Replace(fullPayload, `"cwd"`, `"cwd2"`, 1))
+	Log(drifted, cfg, store, now)
+	Log(drifted, cfg, store, now.Add(time.Minute)) // unchanged drift: logged once
+	want := "2026-03-05T14:30:00Z version=2.1.80 unknown=cwd2 missing=cwd\n"
+	if got := string(store.files[path]); got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+
+	Log([]byte("{"), cfg, store, now)
+	if got := string(store.files[path]); !strings.HasPrefix(got, want) || !strings.Contains(got, "invalid JSON") {
+		t.Errorf("invalid payload not appended: %q", got)
+	}
+	if store.rewrites != 0 {
+		t.Errorf("log rewritten %d times, want entries appended", store.rewrites)
+	}
+
+	// Rotation
+	store.files[path] = []byte(strings.Repeat("x", logMaxSize+1) + "\n")
+	Log(drifted, cfg, store, now)
+	if len(store.files[path+".1"]) != logMaxSize+2 || string(store.files[path]) != want {
+		t.Errorf("rotation: %d bytes rotated, log %q", len(store.files[path+".1"]), store.files[path])
+	}
+}
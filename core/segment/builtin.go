@@ -0,0 +1,330 @@
+package segment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/agents"
+	"github.com/Benniphx/claude-statusline/core/compat"
+	corecontext "github.com/Benniphx/claude-statusline/core/context"
+	"github.com/Benniphx/claude-statusline/core/cost"
+	"github.com/Benniphx/claude-statusline/core/delta"
+	"github.com/Benniphx/claude-statusline/core/git"
+	"github.com/Benniphx/claude-statusline/core/history"
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/ollama"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/transcript"
+	"github.com/Benniphx/claude-statusline/core/types"
+	"github.com/Benniphx/claude-statusline/core/update"
+)
+
+// Dependency keys for the built-in providers.
+const (
+	DepContext    = "context"    // types.ContextDisplay
+	DepAgents     = "agents"     // agents.AgentInfo
+	DepRateLimit  = "ratelimit"  // ratelimit.State
+	DepCost       = "cost"       // cost.State
+	DepOllama     = "ollama"     // *ollama.Stats (nil when missing or stale)
+	DepGit        = "git"        // *git.Status (nil outside a repository)
+	DepTranscript = "transcript" // *transcript.State (nil without a readable transcript)
+	DepDelta      = "delta"      // delta.Delta
+	DepHistory    = "history"    // []history.Point
+	DepForecast   = "forecast"   // history.Forecast
+)
+
+func init() {
+	RegisterProvider(DepContext, func(rc *ports.RenderContext) any {
+		return corecontext.Calculate(rc.Input, rc.Model, rc.Config)
+	})
+	RegisterProvider(DepAgents, func(rc *ports.RenderContext) any {
+		return agents.Count()
+	})
+	RegisterProvider(DepRateLimit, func(rc *ports.RenderContext) any {
+		return ratelimit.Compute(rc.Input, rc.Credentials, rc.Config, rc.Platform, rc.Store, rc.API, rc.Model)
+	})
+	RegisterProvider(DepCost, func(rc *ports.RenderContext) any {
+		return cost.Compute(rc.Input, rc.Config, rc.Platform, rc.Store, rc.Model)
+	})
+	RegisterProvider(DepOllama, func(rc *ports.RenderContext) any {
+		stats, err := ollama.ReadStats(ollama.StatsPath)
+		if err != nil {
+			return (*ollama.Stats)(nil)
+		}
+		return stats
+	})
+	RegisterProvider(DepGit, func(rc *ports.RenderContext) any {
+		return git.Load(rc.Input.Dir(), rc.Config, rc.Store)
+	})
+	RegisterProvider(DepDelta, func(rc *ports.RenderContext) any {
+		frame := delta.Frame{
+			Context: contextDisplay(rc).TokensUsed,
+			Output:  rc.Input.ContextWindow.TotalOutputTokens,
+			Cost:    rc.Input.Cost.TotalCostUSD,
+		}
+		// The transcript counts the output tokens of every API response:
+		// prefer it to the stdin total when Claude Code provides one
+		if st := Get(rc, DepTranscript).(*transcript.State); st != nil && st.Messages > 0 {
+			frame.Output = st.Total.OutputTokens
+		}
+		sessionID, _ := cost.ResolveSession(rc.Input, rc.Platform)
+		return delta.Track(frame, sessionID, rc.Config, rc.Store)
+	})
+	RegisterProvider(DepHistory, func(rc *ports.RenderContext) any {
+		return ratelimit.History(rateState(rc), rc.Credentials, rc.Config, rc.Store, time.Now())
+	})
+	RegisterProvider(DepForecast, func(rc *ports.RenderContext) any {
+		return ratelimit.Forecast(rateState(rc), Get(rc, DepHistory).([]history.Point), time.Now())
+	})
+	RegisterProvider(DepTranscript, func(rc *ports.RenderContext) any {
+		if rc.Input.TranscriptPath == "" {
+			return (*transcript.State)(nil)
+		}
+		st, _, err := transcript.Read(rc.Input.TranscriptPath, rc.Input.SessionID, rc.Config.CacheDir, rc.Store)
+		if err != nil {
+			return (*transcript.State)(nil)
+		}
+		return &st
+	})
+
+	Register(NewCompact(layout.Model, []string{DepContext, DepAgents}, renderModel, renderModelCompact))
+	Register(NewCompact(layout.Context, []string{DepContext}, renderContext, renderContextCompact))
+	Register(NewCompact(layout.FiveHour, []string{DepRateLimit}, renderFiveHour, renderFiveHourCompact))
+	Register(NewCompact(layout.Burn, []string{DepRateLimit, DepCost}, renderBurn, renderBurnCompact))
+	Register(NewCompact(layout.SevenDay, []string{DepRateLimit, DepForecast}, renderSevenDay, renderSevenDayCompact))
+	Register(New(layout.Session, []string{DepCost}, renderSession))
+	Register(New(layout.Daily, []string{DepCost}, renderDaily))
+	Register(New(layout.Duration, []string{DepContext}, renderDuration))
+	Register(New(layout.Lines, []string{DepContext}, renderLines))
+	Register(NewCompact(layout.Ollama, []string{DepOllama}, renderOllama, renderOllamaCompact))
+	Register(New(layout.Update, nil, renderUpdate))
+	Register(New(layout.Workspace, nil, renderWorkspace))
+	Register(New(layout.Version, nil, renderVersion))
+	Register(New(layout.Git, []string{DepGit}, renderGit))
+	Register(New(layout.Delta, []string{DepContext, DepTranscript, DepDelta}, renderDelta))
+	Register(New(layout.History, []string{DepRateLimit, DepHistory}, renderHistory))
+	Register(NewCompact(layout.Extra, []string{DepRateLimit}, renderExtra, renderExtraCompact))
+	Register(New(layout.Account, nil, renderAccount))
+
+	RegisterData(layout.Model, modelData)
+	RegisterData(layout.Context, contextData)
+	RegisterData(layout.FiveHour, fiveHourData)
+	RegisterData(layout.Burn, burnData)
+	RegisterData(layout.SevenDay, sevenDayData)
+	RegisterData(layout.Session, costData)
+	RegisterData(layout.Daily, costData)
+	RegisterData(layout.Duration, durationData)
+	RegisterData(layout.Lines, linesData)
+	RegisterData(layout.Ollama, ollamaData)
+	RegisterData(layout.Workspace, workspaceData)
+	RegisterData(layout.Version, versionData)
+	RegisterData(layout.Git, gitData)
+	RegisterData(layout.Delta, deltaData)
+	RegisterData(layout.History, historyData)
+	RegisterData(layout.Extra, extraData)
+	RegisterData(layout.Account, accountData)
+}
+
+func contextDisplay(rc *ports.RenderContext) types.ContextDisplay {
+	return Get(rc, DepContext).(types.ContextDisplay)
+}
+
+func rateState(rc *ports.RenderContext) ratelimit.State {
+	return Get(rc, DepRateLimit).(ratelimit.State)
+}
+
+func costState(rc *ports.RenderContext) cost.State {
+	return Get(rc, DepCost).(cost.State)
+}
+
+// renderModel shows the model name (colored by context %) + agent count.
+func renderModel(rc *ports.RenderContext) string {
+	s := renderModelCompact(rc)
+	if info := Get(rc, DepAgents).(agents.AgentInfo); info.HasSubagents {
+		s += rc.Renderer.Dim(fmt.Sprintf(" (%d)", info.Total))
+	}
+	return s
+}
+
+func renderModelCompact(rc *ports.RenderContext) string {
+	pct := contextDisplay(rc).PercentUsed
+	name, icon := rc.Model.ShortName, types.IconModel
+	if rc.Model.IsLocal {
+		name, icon = strings.TrimPrefix(name, types.EmojiIcons[types.IconLlama]+" "), types.IconLlama
+	}
+	return withIcon(rc.Renderer, icon, rc.Renderer.Style(name, rc.Config.Thresholds.Context.Role(float64(pct))))
+}
+
+func renderContext(rc *ports.RenderContext) string {
+	return corecontext.Render(contextDisplay(rc), rc.Config, rc.Renderer)
+}
+
+func renderContextCompact(rc *ports.RenderContext) string {
+	return corecontext.RenderCompact(contextDisplay(rc), rc.Config, rc.Renderer)
+}
+
+// Rate limit segments are subscription-only.
+func renderFiveHour(rc *ports.RenderContext) string {
+	if !rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return ratelimit.RenderFiveHour(rateState(rc), rc.Config, rc.Renderer)
+}
+
+// renderBurn shows the rate-based burn for subscriptions, cost-based burn for API keys.
+func renderBurn(rc *ports.RenderContext) string {
+	if rc.Credentials.HasOAuth() {
+		return ratelimit.RenderBurn(rateState(rc), rc.Renderer)
+	}
+	return cost.RenderBurn(costState(rc), rc.Config, rc.Renderer)
+}
+
+func renderSevenDay(rc *ports.RenderContext) string {
+	if !rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return ratelimit.RenderSevenDay(rateState(rc), Get(rc, DepForecast).(history.Forecast), rc.Config, rc.Renderer)
+}
+
+func renderFiveHourCompact(rc *ports.RenderContext) string {
+	return ratelimit.RenderFiveHourCompact(rateState(rc), rc.Config, rc.Renderer)
+}
+
+func renderBurnCompact(rc *ports.RenderContext) string {
+	if rc.Credentials.HasOAuth() {
+		return ratelimit.RenderBurnCompact(rateState(rc), rc.Renderer)
+	}
+	return cost.RenderBurnCompact(costState(rc), rc.Config, rc.Renderer)
+}
+
+func renderSevenDayCompact(rc *ports.RenderContext) string {
+	return ratelimit.RenderSevenDayCompact(rateState(rc), rc.Config, rc.Renderer)
+}
+
+// renderExtra shows the paid usage beyond the limits: "💰 extra $12.34/$50.00".
+func renderExtra(rc *ports.RenderContext) string {
+	if !rc.Credentials.HasOAuth() {
+		return ""
+	}
+	rendered := ratelimit.RenderExtra(rateState(rc), rc.Config, rc.Renderer)
+	if rendered == "" {
+		return ""
+	}
+	return withIcon(rc.Renderer, types.IconCost, rendered)
+}
+
+func renderExtraCompact(rc *ports.RenderContext) string {
+	if !rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return ratelimit.RenderExtraCompact(rateState(rc), rc.Config, rc.Renderer)
+}
+
+// Cost segments are API-key-only.
+func renderSession(rc *ports.RenderContext) string {
+	if rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return cost.RenderSession(costState(rc), rc.Config, rc.Renderer)
+}
+
+func renderDaily(rc *ports.RenderContext) string {
+	if rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return cost.RenderDaily(costState(rc), rc.Config, rc.Renderer)
+}
+
+func renderDuration(rc *ports.RenderContext) string {
+	return withIcon(rc.Renderer, types.IconClock, rc.Renderer.Dim(fmt.Sprintf("%dm", contextDisplay(rc).DurationMin)))
+}
+
+func renderLines(rc *ports.RenderContext) string {
+	d := contextDisplay(rc)
+	if d.LinesAdded == 0 && d.LinesRemoved == 0 {
+		return ""
+	}
+	r := rc.Renderer
+	return r.Style(fmt.Sprintf("+%d", d.LinesAdded), types.RoleOK) + "/" +
+		r.Style(fmt.Sprintf("-%d", d.LinesRemoved), types.RoleCritical)
+}
+
+func renderOllama(rc *ports.RenderContext) string {
+	rendered := ollama.Render(Get(rc, DepOllama).(*ollama.Stats), rc.Renderer.FormatCost)
+	if rendered == "" {
+		return ""
+	}
+	return rc.Renderer.Dim(rendered)
+}
+
+func renderOllamaCompact(rc *ports.RenderContext) string {
+	return rc.Renderer.Dim(ollama.RenderCompact(Get(rc, DepOllama).(*ollama.Stats)))
+}
+
+// withIcon prefixes text with an icon, if the renderer's icon set has one.
+func withIcon(r ports.Renderer, icon types.Icon, text string) string {
+	if glyph := r.Icon(icon); glyph != "" {
+		return glyph + " " + text
+	}
+	return text
+}
+
+// renderWorkspace shows the project and the current directory within it: "statusline/core".
+func renderWorkspace(rc *ports.RenderContext) string {
+	name, subdir := rc.Input.Project()
+	if name == "" {
+		return ""
+	}
+	if subdir != "" {
+		return name + rc.Renderer.Dim("/"+subdir)
+	}
+	return name
+}
+
+// renderAccount shows which subscription the rate limits belong to:
+// "ben@acme", or "#1a2b3c" when the email is unknown.
+func renderAccount(rc *ports.RenderContext) string {
+	if !rc.Credentials.HasOAuth() {
+		return ""
+	}
+	return rc.Renderer.Dim(rc.Credentials.AccountLabel())
+}
+
+func renderGit(rc *ports.RenderContext) string {
+	rendered := git.Render(Get(rc, DepGit).(*git.Status), rc.Renderer)
+	if rendered == "" {
+		return ""
+	}
+	return withIcon(rc.Renderer, types.IconBranch, rendered)
+}
+
+func renderDelta(rc *ports.RenderContext) string {
+	return delta.Render(Get(rc, DepDelta).(delta.Delta), rc.Renderer)
+}
+
+func renderHistory(rc *ports.RenderContext) string {
+	st := rateState(rc)
+	if st.Err != nil {
+		return ""
+	}
+	return history.Render(Get(rc, DepHistory).([]history.Point), st.Data, rc.Config, time.Now(), rc.Renderer)
+}
+
+// renderVersion shows the Claude Code version: "v2.1.80", with the version
+// to upgrade to when it lacks stdin features: "v2.1.70 ↑2.1.80".
+func renderVersion(rc *ports.RenderContext) string {
+	if rc.Input.Version == "" {
+		return ""
+	}
+	text := rc.Renderer.Dim("v" + rc.Input.Version)
+	if target := compat.Upgrade(rc.Input.Version); target != "" {
+		text += " " + rc.Renderer.Style("↑"+target, types.RoleAccent)
+	}
+	return text
+}
+
+func renderUpdate(rc *ports.RenderContext) string {
+	return update.Render(rc.Config.Version, rc.Config.CacheDir, rc.Store, rc.API, rc.Renderer)
+}
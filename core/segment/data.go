@@ -0,0 +1,230 @@
+package segment
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/agents"
+	"github.com/Benniphx/claude-statusline/core/compat"
+	"github.com/Benniphx/claude-statusline/core/delta"
+	"github.com/Benniphx/claude-statusline/core/git"
+	"github.com/Benniphx/claude-statusline/core/history"
+	"github.com/Benniphx/claude-statusline/core/ollama"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func modelData(rc *ports.RenderContext) Data {
+	info := Get(rc, DepAgents).(agents.AgentInfo)
+	name := rc.Model.ShortName
+	if rc.Model.IsLocal {
+		name = strings.TrimPrefix(name, types.EmojiIcons[types.IconLlama]+" ")
+	}
+	return &ModelData{
+		Name:      name,
+		Percent:   contextDisplay(rc).PercentUsed,
+		IsLocal:   rc.Model.IsLocal,
+		Agents:    info.Total,
+		Subagents: info.HasSubagents,
+	}
+}
+
+func contextData(rc *ports.RenderContext) Data {
+	d := contextDisplay(rc)
+	bar := rc.Config.ContextBar
+	bar.Levels = rc.Config.Thresholds.Context
+	return &ContextData{
+		Percent:  d.PercentUsed,
+		Used:     d.TokensUsed,
+		Total:    d.TokensTotal,
+		Initial:  d.IsInitial,
+		Bar:      rc.Renderer.MakeBar(d.PercentUsed, bar),
+		Over200K: rc.Input.Exceeds200KTokens,
+	}
+}
+
+func fiveHourData(rc *ports.RenderContext) Data {
+	st := rateState(rc)
+	if st.Err != nil {
+		return nil
+	}
+	pct := int(math.Round(st.Data.FiveHourPercent))
+	bar := rc.Config.FiveHourBar
+	bar.Levels = rc.Config.Thresholds.FiveHour
+	return &WindowData{
+		Name:         types.WindowFiveHour,
+		Percent:      pct,
+		Used:         st.Data.FiveHourPercent,
+		Elapsed:      st.Pace.FiveHourTimePct,
+		Bar:          rc.Renderer.MakeSplitBar(pct, st.Pace.FiveHourTimePct, bar),
+		Pace:         round1(st.Pace.FiveHourPace * st.Norm.Mult),
+		PacePrefix:   st.Norm.Prefix,
+		HittingLimit: st.Pace.HittingLimit,
+		LimitETA:     st.Pace.LimitETA,
+		ResetIn:      st.Pace.ResetIn,
+		ResetAt:      st.Pace.ResetAt,
+		ResetsAt:     st.Data.FiveHourReset,
+		Windows:      st.Data.Windows,
+	}
+}
+
+func sevenDayData(rc *ports.RenderContext) Data {
+	st := rateState(rc)
+	if st.Err != nil {
+		return nil
+	}
+	weekly := st.SevenDay()
+	fc := Get(rc, DepForecast).(history.Forecast)
+	pct := int(math.Round(weekly.Percent))
+	bar := rc.Config.SevenDayBar
+	bar.Levels = rc.Config.Thresholds.SevenDay
+	d := &WindowData{
+		Name:       weekly.Name,
+		Percent:    pct,
+		Used:       weekly.Percent,
+		Elapsed:    st.Pace.SevenDayTimePct,
+		Bar:        rc.Renderer.MakeSplitBar(pct, st.Pace.SevenDayTimePct, bar),
+		Pace:       round1(st.Pace.SevenDayPace * st.Norm.Mult),
+		PacePrefix: st.Norm.Prefix,
+		ResetIn:    st.Pace.SevenDayResetIn,
+		ResetsAt:   weekly.Reset,
+		Windows:    st.Data.Windows,
+		ExhaustAt:  fc.ExhaustAt,
+		Confidence: fc.Confidence.String(),
+	}
+	if !d.ExhaustAt.IsZero() {
+		d.ExhaustETA = rc.Config.Locale.DayTime(d.ExhaustAt)
+	}
+	return d
+}
+
+func burnData(rc *ports.RenderContext) Data {
+	if !rc.Credentials.HasOAuth() {
+		st := costState(rc)
+		return &BurnData{TPM: st.LocalTPM, Prefix: st.Norm.Prefix, PerHour: st.Display.CostPerHour}
+	}
+	st := rateState(rc)
+	if st.Err != nil {
+		return nil
+	}
+	return &BurnData{
+		TPM:          int(math.Round(st.Burn.LocalTPM * st.Norm.Mult)),
+		GlobalTPM:    int(math.Round(st.Burn.GlobalTPM * st.Norm.Mult)),
+		HighActivity: st.Burn.IsHighActivity,
+		Prefix:       st.Norm.Prefix,
+	}
+}
+
+func costData(rc *ports.RenderContext) Data {
+	d := costState(rc).Display
+	return &CostData{Session: d.SessionCost, Daily: d.DailyCost, PerHour: d.CostPerHour}
+}
+
+func durationData(rc *ports.RenderContext) Data {
+	min := contextDisplay(rc).DurationMin
+	api := time.Duration(rc.Input.Cost.TotalAPIDurationMS) * time.Millisecond
+	return &DurationData{
+		Minutes:     min,
+		Duration:    time.Duration(min) * time.Minute,
+		APIMinutes:  int(api.Minutes()),
+		APIDuration: api,
+	}
+}
+
+func linesData(rc *ports.RenderContext) Data {
+	d := contextDisplay(rc)
+	return &LinesData{Added: d.LinesAdded, Removed: d.LinesRemoved}
+}
+
+func workspaceData(rc *ports.RenderContext) Data {
+	name, subdir := rc.Input.Project()
+	if name == "" {
+		return nil
+	}
+	return &WorkspaceData{Project: name, Subdir: subdir, Dir: rc.Input.Dir()}
+}
+
+func accountData(rc *ports.RenderContext) Data {
+	creds := rc.Credentials
+	if !creds.HasOAuth() {
+		return nil
+	}
+	return &AccountData{Label: creds.AccountLabel(), Email: creds.Email, Key: creds.AccountKey()}
+}
+
+func versionData(rc *ports.RenderContext) Data {
+	if rc.Input.Version == "" {
+		return nil
+	}
+	var missing []string
+	for _, f := range compat.Missing(rc.Input.Version) {
+		missing = append(missing, string(f))
+	}
+	return &VersionData{Version: rc.Input.Version, Upgrade: compat.Upgrade(rc.Input.Version), Missing: missing}
+}
+
+func gitData(rc *ports.RenderContext) Data {
+	st := Get(rc, DepGit).(*git.Status)
+	if st == nil {
+		return nil
+	}
+	return &GitData{
+		Branch:   st.Branch,
+		SHA:      st.ShortSHA(),
+		Detached: st.Detached(),
+		Staged:   st.Staged,
+		Dirty:    st.Dirty,
+		Upstream: st.Upstream,
+		Ahead:    st.Ahead,
+		Behind:   st.Behind,
+	}
+}
+
+func deltaData(rc *ports.RenderContext) Data {
+	d := Get(rc, DepDelta).(delta.Delta)
+	if d.IsZero() {
+		return nil
+	}
+	return &DeltaData{Context: d.Context, Output: d.Output, Cost: d.Cost}
+}
+
+func historyData(rc *ports.RenderContext) Data {
+	st := rateState(rc)
+	if st.Err != nil {
+		return nil
+	}
+	points := Get(rc, DepHistory).([]history.Point)
+	now := time.Now()
+	return &HistoryData{
+		FiveHour: history.FiveHour(points, st.Data.FiveHourReset, now),
+		SevenDay: history.SevenDay(points, now),
+		Points:   len(points),
+	}
+}
+
+func extraData(rc *ports.RenderContext) Data {
+	st := rateState(rc)
+	if st.Err != nil || !st.Data.Extra.Enabled {
+		return nil
+	}
+	extra := st.Data.Extra
+	return &ExtraData{Used: extra.Used, Limit: extra.Limit, Percent: extra.Percent()}
+}
+
+func ollamaData(rc *ports.RenderContext) Data {
+	stats := Get(rc, DepOllama).(*ollama.Stats)
+	if stats == nil {
+		return nil
+	}
+	return &OllamaData{
+		Requests:         stats.Requests,
+		PromptTokens:     stats.TotalPromptTokens,
+		CompletionTokens: stats.TotalCompletionTokens,
+		Saved:            ollama.Savings(stats),
+	}
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,290 @@
+package segment
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// DataFunc computes the typed data a segment exposes to user format templates.
+// Returning nil keeps the built-in rendering (e.g. when data is unavailable).
+type DataFunc func(rc *ports.RenderContext) Data
+
+// Data is implemented by the segment data structs by embedding Base.
+type Data interface {
+	base() *Base
+}
+
+// Base holds the fields every segment exposes to format templates.
+type Base struct {
+	Default string      // The built-in rendering of the segment
+	Input   types.Input // Claude Code input, e.g. {{.Input.Version}} or {{.Input.OutputStyle.Name}}
+}
+
+func (b *Base) base() *Base { return b }
+
+var dataFuncs = map[string]DataFunc{}
+
+// RegisterData adds the template data of a segment. Segments without data
+// still accept templates, with only the Base fields available.
+func RegisterData(name string, fn DataFunc) {
+	if _, dup := dataFuncs[name]; dup {
+		panic(fmt.Sprintf("segment: duplicate data for %q", name))
+	}
+	dataFuncs[name] = fn
+}
+
+// ModelData is the template data of the model segment.
+type ModelData struct {
+	Base
+	Name      string
+	Percent   int // Context usage, which colors the name
+	IsLocal   bool
+	Agents    int // Running Claude Code processes
+	Subagents bool
+}
+
+// ContextData is the template data of the context segment.
+type ContextData struct {
+	Base
+	Percent  int
+	Used     int // Tokens
+	Total    int // Tokens
+	Initial  bool
+	Bar      string
+	Over200K bool // Total tokens exceed 200K (long-context pricing)
+}
+
+// WindowData is the template data of the 5h and 7d segments.
+type WindowData struct {
+	Base
+	Name         string  // "5h", "7d", or the active model's weekly window when used more, e.g. "7d-opus"
+	Percent      int     // Usage, rounded
+	Used         float64 // Usage, unrounded
+	Elapsed      int     // Percentage of the window elapsed
+	Bar          string  // Usage bar with elapsed-time marker
+	Pace         float64 // Cost-normalized pace, one decimal (0 = unknown)
+	PacePrefix   string  // "≈" when cost-normalized
+	HittingLimit bool    // 5h only
+	LimitETA     string  // 5h only: "14:30" when hitting the limit
+	ResetIn      string  // "45m" (5h, within the last hour) or "2d" (7d, within 3 days)
+	ResetAt      string  // 5h only: "14:30" within the last 30 minutes
+	ResetsAt     time.Time
+	ExhaustAt    time.Time          // 7d only: projected exhaustion before the reset (zero = none)
+	ExhaustETA   string             // 7d only: "Thu 15:00" when projected to run out
+	Confidence   string             // 7d only: forecast confidence, "none", "low", "medium" or "high"
+	Windows      []types.RateWindow // Every window: {{range .Windows}}{{.Name}} {{.Percent}}{{end}}
+}
+
+// BurnData is the template data of the burn segment.
+type BurnData struct {
+	Base
+	TPM          int     // Local tokens per minute, cost-normalized
+	GlobalTPM    int     // All sessions, cost-normalized (subscriptions only)
+	HighActivity bool    // Other sessions or subagents are active
+	Prefix       string  // "≈" when cost-normalized
+	PerHour      float64 // Cost per hour (API key only)
+}
+
+// CostData is the template data of the session and daily segments.
+type CostData struct {
+	Base
+	Session float64
+	Daily   float64
+	PerHour float64
+}
+
+// DurationData is the template data of the duration segment.
+type DurationData struct {
+	Base
+	Minutes     int
+	Duration    time.Duration
+	APIMinutes  int           // Time spent waiting for API responses
+	APIDuration time.Duration // Time spent waiting for API responses
+}
+
+// WorkspaceData is the template data of the workspace segment.
+type WorkspaceData struct {
+	Base
+	Project string // Last element of the project directory
+	Subdir  string // Current directory relative to the project ("" at the root)
+	Dir     string // Current directory
+}
+
+// AccountData is the template data of the account segment.
+type AccountData struct {
+	Base
+	Label string // Short name: "ben@acme", or "#1a2b3c" without an email
+	Email string // "" when unknown (token from the environment)
+	Key   string // Hash keying the account's cache files
+}
+
+// VersionData is the template data of the version segment.
+type VersionData struct {
+	Base
+	Version string   // Claude Code version
+	Upgrade string   // Version providing the missing stdin features ("" = up to date)
+	Missing []string // Stdin fields this version lacks, e.g. "rate_limits"
+}
+
+// LinesData is the template data of the lines segment.
+type LinesData struct {
+	Base
+	Added   int
+	Removed int
+}
+
+// GitData is the template data of the git segment.
+type GitData struct {
+	Base
+	Branch   string // "" when detached
+	SHA      string // Short commit of HEAD
+	Detached bool
+	Staged   int
+	Dirty    int    // Unstaged changes, conflicts and untracked files
+	Upstream string // e.g. "origin/main"
+	Ahead    int
+	Behind   int
+}
+
+// HistoryData is the template data of the history segment.
+type HistoryData struct {
+	Base
+	FiveHour string // Sparkline of the current 5h window, one bar per 30 minutes
+	SevenDay string // Sparkline of the last 7 days, one bar per 12 hours
+	Points   int    // Points recorded in the last 8 days
+}
+
+// ExtraData is the template data of the extra segment: the paid usage beyond
+// the limits this month.
+type ExtraData struct {
+	Base
+	Used    float64 // USD
+	Limit   float64 // Monthly limit in USD (0 = none)
+	Percent float64 // Share of the limit used (0 without a limit)
+}
+
+// DeltaData is the template data of the delta segment: the last change.
+type DeltaData struct {
+	Base
+	Context int     // Tokens added to the context (negative after compaction)
+	Output  int     // Output tokens
+	Cost    float64 // USD
+}
+
+// OllamaData is the template data of the ollama segment.
+type OllamaData struct {
+	Base
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Saved            float64 // Estimated USD saved versus Haiku
+}
+
+// applyFormat renders the user template configured for a segment, given its
+// built-in rendering. Segments that render empty stay empty, and templates
+// that fail to parse or execute fall back to the built-in rendering.
+func applyFormat(name, text string, rc *ports.RenderContext) string {
+	format := rc.Config.Formats[name]
+	if format == "" || text == "" {
+		return text
+	}
+	var data Data = &Base{}
+	if fn, ok := dataFuncs[name]; ok {
+		if data = fn(rc); data == nil {
+			return text
+		}
+	}
+	data.base().Default = text
+	data.base().Input = rc.Input
+
+	funcs := templateFuncs(rc.Renderer, metricLevels(name, rc.Config.Thresholds))
+	tmpl, err := template.New(name).Funcs(funcs).Parse(format)
+	if err != nil {
+		return text
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return text
+	}
+	return b.String()
+}
+
+// metricLevels returns the threshold coloring a segment's percentages in
+// format templates: the model shows the context, segments without a
+// percentage threshold use 50/80.
+func metricLevels(name string, th types.Thresholds) types.Threshold {
+	switch name {
+	case layout.Model, layout.Context:
+		return th.Context
+	case layout.FiveHour:
+		return th.FiveHour
+	case layout.SevenDay:
+		return th.SevenDay
+	case layout.Extra:
+		return th.Extra
+	}
+	return types.Threshold{Warn: 50, Crit: 80}
+}
+
+// templateFuncs returns the helper functions available to format templates,
+// coloring percentages and bars by levels. Styling helpers take the text
+// last, so they work at the end of a pipeline:
+//
+//	{{.Percent | printf "%d%%" | Colorize .Percent}}
+func templateFuncs(r ports.Renderer, levels types.Threshold) template.FuncMap {
+	bar := func(width int) types.BarStyle {
+		b := barWidth(width)
+		b.Levels = levels
+		return b
+	}
+	return template.FuncMap{
+		"Style":    func(role, text string) string { return r.Style(text, types.Role(role)) },
+		"Colorize": func(pct any, text string) string { return r.Style(text, levels.Role(number(pct))) },
+		"Dim":      r.Dim,
+		"Level": func(value, warn, crit any) string {
+			return string(types.LevelRole(number(value), number(warn), number(crit)))
+		},
+		"Icon":           func(name string) string { return r.Icon(types.Icon(name)) },
+		"Bar":            func(pct, width int) string { return r.MakeBar(pct, bar(width)) },
+		"SplitBar":       func(usage, elapsed, width int) string { return r.MakeSplitBar(usage, elapsed, bar(width)) },
+		"FormatTokens":   func(n any) string { return r.FormatTokens(int(number(n))) },
+		"FormatTokensF":  func(n any) string { return r.FormatTokensF(int(number(n))) },
+		"FormatCost":     func(f any) string { return r.FormatCost(number(f)) },
+		"FormatDuration": formatDuration,
+	}
+}
+
+// barWidth returns the default bar with the given width.
+func barWidth(width int) types.BarStyle {
+	bar := types.DefaultBar()
+	bar.Width = width
+	return bar
+}
+
+// number converts template numbers (int or float literals and fields) to float64.
+func number(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// formatDuration formats a duration as "1h5m" or "45m".
+func formatDuration(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	if h > 0 {
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
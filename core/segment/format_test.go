@@ -0,0 +1,132 @@
+package segment
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+func TestApplyFormat(t *testing.T) {
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 60000},
+		},
+		Cost:        types.Cost{TotalDurationMS: 65 * 60000, TotalAPIDurationMS: 24 * 60000, TotalLinesAdded: 12, TotalLinesRemoved: 3},
+		Workspace:   types.Workspace{CurrentDir: "/src/app/core", ProjectDir: "/src/app"},
+		OutputStyle: types.OutputStyle{Name: "Explanatory"},
+	}
+	rate := ratelimit.State{
+		Data: types.RateLimitData{FiveHourPercent: 46.4, SevenDayPercent: 27},
+		Pace: types.PaceInfo{FiveHourPace: 1.24, ResetIn: "45m"},
+		Norm: types.CostNorm{Mult: 1.0},
+	}
+
+	tests := []struct {
+		name    string
+		segment string
+		format  string
+		want    string
+	}{
+		{"no format keeps built-in", layout.Lines, "", "+12/-3"},
+		{"default field", layout.Lines, "[{{.Default}}]", "[+12/-3]"},
+		{"typed fields", layout.Lines, "{{.Added}} added, {{.Removed}} removed", "12 added, 3 removed"},
+		{"5h example", layout.FiveHour, "{{.Bar}} {{.Percent}}% {{.Pace}}x →{{.ResetIn}}", "[split] 46% 1.2x →45m"},
+		{"bar helper", layout.Context, "{{Bar .Percent 4}} {{.Percent}}%", "[bar] 30%"},
+		{"duration helper", layout.Duration, "⏱ {{FormatDuration .Duration}}", "⏱ 1h5m"},
+		{"api duration", layout.Duration, "{{.Minutes}}m ({{FormatDuration .APIDuration}} api)", "65m (24m api)"},
+		{"workspace fields", layout.Workspace, "{{.Project}}:{{.Subdir}}", "app:core"},
+		{"input fields", layout.Lines, "{{.Input.OutputStyle.Name}} {{.Added}}", "Explanatory 12"},
+		{"level helper", layout.SevenDay, `{{Level .Percent 50 80}}`, "ok"},
+		{"base only", layout.Update, "x{{.Default}}", ""}, // empty segments stay empty
+		{"parse error falls back", layout.Lines, "{{.Added", "+12/-3"},
+		{"exec error falls back", layout.Lines, "{{.Nope}}", "+12/-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := newContext(input, types.Credentials{OAuthToken: "token"})
+			rc.Config.Formats = map[string]string{tt.segment: tt.format}
+			rc.Value(DepRateLimit, func() any { return rate })
+			rc.Config.Version = "" // no update check
+
+			sections := Build([]string{tt.segment}, rc)
+			if len(sections) != 1 {
+				t.Fatalf("Build returned %d sections", len(sections))
+			}
+			if got := sections[0].Text; got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.segment, got, tt.want)
+			}
+		})
+	}
+}
+
+// levelRenderer shows the role of styled text and the levels of bars.
+type levelRenderer struct{ mockRenderer }
+
+func (r *levelRenderer) Style(text string, role types.Role) string {
+	return "<" + string(role) + ">" + text
+}
+func (r *levelRenderer) MakeBar(percent int, bar types.BarStyle) string {
+	return fmt.Sprintf("[bar %v/%v]", bar.Levels.Warn, bar.Levels.Crit)
+}
+
+func TestFormatHelpersUseThresholds(t *testing.T) {
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 60000},
+		},
+	}
+	format := `{{Bar .Percent 4}} {{.Percent | printf "%d%%" | Colorize .Percent}}`
+
+	tests := []struct {
+		name      string
+		threshold types.Threshold
+		want      string
+	}{
+		{"default", types.Threshold{Warn: 50, Crit: 80}, "[bar 50/80] <ok>30%"},
+		{"strict context", types.Threshold{Warn: 10, Crit: 20}, "[bar 10/20] <critical>30%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := newContext(input, types.Credentials{})
+			rc.Renderer = &levelRenderer{}
+			rc.Config.Thresholds.Context = tt.threshold
+			rc.Config.Formats = map[string]string{layout.Context: format}
+			if got := Build([]string{layout.Context}, rc)[0].Text; got != tt.want {
+				t.Errorf("context = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFormatUnavailableData(t *testing.T) {
+	rc := newContext(types.Input{}, types.Credentials{OAuthToken: "token"})
+	rc.Config.Formats = map[string]string{layout.FiveHour: "{{.Percent}}%"}
+	rc.Value(DepRateLimit, func() any { return ratelimit.State{Err: errors.New("unavailable")} })
+
+	if got := Build([]string{layout.FiveHour}, rc)[0].Text; got != "5h: --" {
+		t.Errorf("5h without data = %q, want built-in %q", got, "5h: --")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{45 * time.Minute, "45m"},
+		{125 * time.Minute, "2h5m"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
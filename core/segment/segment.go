@@ -0,0 +1,124 @@
+package segment
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/ports"
+)
+
+// Provider computes a shared value that segments declare as a dependency.
+type Provider func(rc *ports.RenderContext) any
+
+var (
+	segments  = map[string]ports.Segment{}
+	providers = map[string]Provider{}
+)
+
+// Register adds a segment to the registry. It panics on duplicate names,
+// so conflicting registrations fail at startup rather than silently.
+func Register(s ports.Segment) {
+	if _, dup := segments[s.Name()]; dup {
+		panic(fmt.Sprintf("segment: duplicate registration of %q", s.Name()))
+	}
+	segments[s.Name()] = s
+}
+
+// RegisterProvider adds a shared data provider under the given dependency key.
+func RegisterProvider(key string, p Provider) {
+	if _, dup := providers[key]; dup {
+		panic(fmt.Sprintf("segment: duplicate provider %q", key))
+	}
+	providers[key] = p
+}
+
+// Lookup returns the registered segment with the given name.
+func Lookup(name string) (ports.Segment, bool) {
+	s, ok := segments[name]
+	return s, ok
+}
+
+// Names returns all registered segment names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(segments))
+	for name := range segments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the shared value for a dependency key, computing it on first use.
+// Returns nil when no provider is registered for key.
+func Get(rc *ports.RenderContext, key string) any {
+	p, ok := providers[key]
+	if !ok {
+		return nil
+	}
+	return rc.Value(key, func() any { return p(rc) })
+}
+
+// Build renders the named segments in order, applying user format templates.
+// Unknown names and segments with unregistered dependencies are skipped.
+func Build(names []string, rc *ports.RenderContext) []layout.Section {
+	var sections []layout.Section
+	for _, name := range names {
+		s, ok := Lookup(name)
+		if !ok || !resolvable(s.Dependencies()) {
+			continue
+		}
+		section := layout.Section{
+			Name:     name,
+			Text:     applyFormat(name, s.Render(rc), rc),
+			Glue:     layout.GlueFor(name),
+			Priority: layout.PriorityFor(name),
+		}
+		// The format applies to the compact form too, with .Default being the
+		// compact rendering; a template without .Default does not shrink.
+		if cs, ok := s.(ports.CompactSegment); ok && section.Text != "" {
+			section.Compact = applyFormat(name, cs.RenderCompact(rc), rc)
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// resolvable reports whether every dependency has a registered provider.
+// Values themselves are computed lazily by Get, so a segment that only
+// needs one of its dependencies in the current mode never pays for the other.
+func resolvable(deps []string) bool {
+	for _, dep := range deps {
+		if _, ok := providers[dep]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// New adapts a render function into a ports.Segment.
+func New(name string, deps []string, render func(rc *ports.RenderContext) string) ports.Segment {
+	return &funcSegment{name: name, deps: deps, render: render}
+}
+
+// NewCompact adapts a render function and its compact form into a ports.CompactSegment.
+func NewCompact(name string, deps []string, render, compact func(rc *ports.RenderContext) string) ports.CompactSegment {
+	return &compactSegment{funcSegment{name: name, deps: deps, render: render}, compact}
+}
+
+type funcSegment struct {
+	name   string
+	deps   []string
+	render func(rc *ports.RenderContext) string
+}
+
+type compactSegment struct {
+	funcSegment
+	compact func(rc *ports.RenderContext) string
+}
+
+func (s *compactSegment) RenderCompact(rc *ports.RenderContext) string { return s.compact(rc) }
+
+func (s *funcSegment) Name() string                          { return s.name }
+func (s *funcSegment) Dependencies() []string                { return s.deps }
+func (s *funcSegment) Render(rc *ports.RenderContext) string { return s.render(rc) }
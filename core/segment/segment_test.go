@@ -0,0 +1,473 @@
+package segment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/agents"
+	"github.com/Benniphx/claude-statusline/core/delta"
+	"github.com/Benniphx/claude-statusline/core/git"
+	"github.com/Benniphx/claude-statusline/core/history"
+	"github.com/Benniphx/claude-statusline/core/layout"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/transcript"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockRenderer implements ports.Renderer without escape codes.
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string      { return text }
+func (m *mockRenderer) Dim(text string) string                         { return text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
+	return "[split]"
+}
+func (m *mockRenderer) FormatTokens(n int) string   { return "" }
+func (m *mockRenderer) FormatTokensF(n int) string  { return "" }
+func (m *mockRenderer) FormatCost(f float64) string { return "" }
+func (m *mockRenderer) Icon(name types.Icon) string { return types.EmojiIcons[name] }
+
+// memStore implements ports.CacheStore in memory.
+type memStore struct{ files map[string][]byte }
+
+func newMemStore() *memStore { return &memStore{files: map[string][]byte{}} }
+
+func (m *memStore) AtomicWrite(path string, data []byte) error { m.files[path] = data; return nil }
+func (m *memStore) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) {
+	data, ok := m.files[path]
+	return data, ok
+}
+func (m *memStore) ReadFile(path string) ([]byte, error) {
+	if data, ok := m.files[path]; ok {
+		return data, nil
+	}
+	return nil, errors.New("not found")
+}
+func (m *memStore) WriteFile(path string, data []byte) error { m.files[path] = data; return nil }
+func (m *memStore) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+func (m *memStore) FileMTime(path string) (time.Time, error) { return time.Time{}, nil }
+func (m *memStore) CleanOld(dir, pattern, keep string) error { return nil }
+
+func newContext(input types.Input, creds types.Credentials) *ports.RenderContext {
+	rc := &ports.RenderContext{
+		Input:       input,
+		Model:       types.ModelInfo{ShortName: "Opus 4.6", DefaultContext: 200000},
+		Config:      types.DefaultConfig(),
+		Renderer:    &mockRenderer{},
+		Credentials: creds,
+		Store:       newMemStore(),
+	}
+	// Agents are counted from the running processes; keep the host's out
+	rc.Value(DepAgents, func() any { return agents.AgentInfo{} })
+	return rc
+}
+
+func TestBuiltinsRegistered(t *testing.T) {
+	for _, name := range append(layout.DefaultOAuth, layout.Session, layout.Daily) {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("built-in segment %q not registered", name)
+		}
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a duplicate name should panic")
+		}
+	}()
+	Register(New(layout.Model, nil, func(rc *ports.RenderContext) string { return "" }))
+}
+
+func TestBuildSkipsUnknownAndUnresolvable(t *testing.T) {
+	Register(New("test-missing-dep", []string{"no-such-provider"}, func(rc *ports.RenderContext) string {
+		return "should not render"
+	}))
+
+	rc := newContext(types.Input{Cost: types.Cost{TotalDurationMS: 120000}}, types.Credentials{})
+	sections := Build([]string{"nonexistent", "test-missing-dep", layout.Duration}, rc)
+
+	if len(sections) != 1 {
+		t.Fatalf("got %d sections, want 1: %+v", len(sections), sections)
+	}
+	if sections[0].Name != layout.Duration || sections[0].Text != "2m" {
+		t.Errorf("section = %+v, want duration 2m", sections[0])
+	}
+}
+
+func TestProviderComputedOncePerRender(t *testing.T) {
+	calls := 0
+	RegisterProvider("test-counter", func(rc *ports.RenderContext) any {
+		calls++
+		return calls
+	})
+	Register(New("test-shared-a", []string{"test-counter"}, func(rc *ports.RenderContext) string {
+		Get(rc, "test-counter")
+		return "a"
+	}))
+	Register(New("test-shared-b", []string{"test-counter"}, func(rc *ports.RenderContext) string {
+		Get(rc, "test-counter")
+		return "b"
+	}))
+
+	rc := newContext(types.Input{}, types.Credentials{})
+	Build([]string{"test-shared-a", "test-shared-b"}, rc)
+	if calls != 1 {
+		t.Errorf("provider called %d times, want 1", calls)
+	}
+
+	// A fresh render context recomputes.
+	Build([]string{"test-shared-a"}, newContext(types.Input{}, types.Credentials{}))
+	if calls != 2 {
+		t.Errorf("provider called %d times after second render, want 2", calls)
+	}
+}
+
+func TestHistoryReadOnlyWhenShown(t *testing.T) {
+	rc := newContext(types.Input{}, types.Credentials{OAuthToken: "token"})
+	store := rc.Store.(*memStore)
+	rc.Value(DepRateLimit, func() any {
+		return ratelimit.State{Data: types.RateLimitData{FiveHourPercent: 46, SevenDayPercent: 27, Source: types.RateSourceStdin}}
+	})
+
+	Build([]string{layout.FiveHour, layout.Burn}, rc)
+	if len(store.files) != 0 {
+		t.Fatalf("5h and burn touched %d cache files, want the history left alone", len(store.files))
+	}
+	Build([]string{layout.SevenDay}, rc)
+	if len(store.files) != 1 {
+		t.Errorf("7d wrote %d cache files, want the history point for its forecast", len(store.files))
+	}
+}
+
+func TestModeSpecificSegmentsHidden(t *testing.T) {
+	input := types.Input{Cost: types.Cost{TotalDurationMS: 120000}}
+
+	// API key: rate limit segments hidden without touching rate data
+	rc := newContext(input, types.Credentials{APIKey: "key"})
+	for _, name := range []string{layout.FiveHour, layout.SevenDay} {
+		s, _ := Lookup(name)
+		if got := s.Render(rc); got != "" {
+			t.Errorf("%s in API-key mode = %q, want empty", name, got)
+		}
+	}
+
+	// OAuth: cost segments hidden without touching cost tracking
+	rc = newContext(input, types.Credentials{OAuthToken: "token"})
+	for _, name := range []string{layout.Session, layout.Daily} {
+		s, _ := Lookup(name)
+		if got := s.Render(rc); got != "" {
+			t.Errorf("%s in OAuth mode = %q, want empty", name, got)
+		}
+	}
+}
+
+func TestRenderLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		added   int
+		removed int
+		want    string
+	}{
+		{"no changes", 0, 0, ""},
+		{"added and removed", 142, 38, "+142/-38"},
+		{"only removed", 0, 5, "+0/-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := types.Input{Cost: types.Cost{TotalLinesAdded: tt.added, TotalLinesRemoved: tt.removed}}
+			rc := newContext(input, types.Credentials{})
+			s, _ := Lookup(layout.Lines)
+			if got := s.Render(rc); got != tt.want {
+				t.Errorf("lines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSessionSegments(t *testing.T) {
+	input := types.Input{
+		Version:   "2.1.80",
+		Workspace: types.Workspace{CurrentDir: "/src/app/core", ProjectDir: "/src/app"},
+	}
+	rc := newContext(input, types.Credentials{})
+
+	ws, _ := Lookup(layout.Workspace)
+	if got := ws.Render(rc); got != "app/core" {
+		t.Errorf("workspace = %q, want app/core", got)
+	}
+	v, _ := Lookup(layout.Version)
+	if got := v.Render(rc); got != "v2.1.80" {
+		t.Errorf("version = %q, want v2.1.80", got)
+	}
+
+	// Versions before native rate_limits get an upgrade hint
+	rc = newContext(types.Input{Version: "2.1.70"}, types.Credentials{})
+	if got := v.Render(rc); got != "v2.1.70 ↑2.1.80" {
+		t.Errorf("version = %q, want v2.1.70 ↑2.1.80", got)
+	}
+
+	// Older Claude Code versions send neither
+	empty := newContext(types.Input{}, types.Credentials{})
+	if got := ws.Render(empty) + v.Render(empty); got != "" {
+		t.Errorf("without workspace and version should render empty, got %q", got)
+	}
+}
+
+func TestRenderGitSegment(t *testing.T) {
+	s, _ := Lookup(layout.Git)
+
+	rc := newContext(types.Input{}, types.Credentials{})
+	rc.Value(DepGit, func() any { return &git.Status{Branch: "main", Staged: 1, Ahead: 2} })
+	if got := s.Render(rc); got != "main +1 ↑2" {
+		t.Errorf("git = %q, want %q", got, "main +1 ↑2")
+	}
+
+	outside := newContext(types.Input{}, types.Credentials{})
+	outside.Value(DepGit, func() any { return (*git.Status)(nil) })
+	if got := s.Render(outside); got != "" {
+		t.Errorf("git outside a repository = %q, want empty", got)
+	}
+}
+
+// numberRenderer formats tokens and cost like the real renderers, unstyled.
+type numberRenderer struct{ mockRenderer }
+
+func (r *numberRenderer) FormatTokens(n int) string   { return types.Locale{}.Tokens(n, 0) }
+func (r *numberRenderer) FormatTokensF(n int) string  { return types.Locale{}.Tokens(n, 1) }
+func (r *numberRenderer) FormatCost(f float64) string { return types.Locale{}.Cost(f) }
+
+func TestRenderDeltaSegment(t *testing.T) {
+	s, _ := Lookup(layout.Delta)
+
+	tests := []struct {
+		name  string
+		delta delta.Delta
+		want  string
+	}{
+		{"context, output and cost", delta.Delta{Context: 1200, Output: 8500, Cost: 0.5}, "Δ +1.2K · 8.5K out · $0.50"},
+		{"context only", delta.Delta{Context: 1200}, "Δ +1.2K"},
+		{"compaction shrinks the context", delta.Delta{Context: -45000, Output: 2000, Cost: 0.12}, "Δ -45.0K · 2.0K out · $0.12"},
+		{"no change", delta.Delta{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := newContext(types.Input{}, types.Credentials{})
+			rc.Renderer = &numberRenderer{}
+			rc.Value(DepDelta, func() any { return tt.delta })
+			if got := s.Render(rc); got != tt.want {
+				t.Errorf("delta = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeltaOutputFromTranscript(t *testing.T) {
+	store := newMemStore()
+	frame := func(stdinOutput int, st *transcript.State) delta.Delta {
+		input := types.Input{SessionID: "s1", ContextWindow: types.ContextWindow{TotalOutputTokens: stdinOutput}}
+		rc := newContext(input, types.Credentials{})
+		rc.Store = store
+		rc.Value(DepTranscript, func() any { return st })
+		return Get(rc, DepDelta).(delta.Delta)
+	}
+
+	frame(100, &transcript.State{Messages: 1, Total: transcript.Usage{OutputTokens: 400}})
+	if got := frame(150, &transcript.State{Messages: 3, Total: transcript.Usage{OutputTokens: 1250}}); got.Output != 850 {
+		t.Errorf("output delta = %d, want 850 from the transcript", got.Output)
+	}
+	if got := frame(1450, nil); got.Output != 200 {
+		t.Errorf("output delta without transcript = %d, want 200 from stdin", got.Output)
+	}
+}
+
+func TestRenderContextSegment(t *testing.T) {
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 60000},
+		},
+		Cost: types.Cost{TotalDurationMS: 60000},
+	}
+	rc := newContext(input, types.Credentials{})
+	s, _ := Lookup(layout.Context)
+	if got, want := s.Render(rc), "Ctx: [bar] 30% (/)"; got != want {
+		t.Errorf("context = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFillsCompactAndPriority(t *testing.T) {
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 60000},
+		},
+		Cost: types.Cost{TotalDurationMS: 60000},
+	}
+	rc := newContext(input, types.Credentials{})
+	sections := Build([]string{layout.Context, layout.Duration}, rc)
+
+	if got, want := sections[0].Compact, "Ctx: 30%"; got != want {
+		t.Errorf("context compact = %q, want %q", got, want)
+	}
+	if sections[0].Priority != layout.PriorityFor(layout.Context) {
+		t.Errorf("context priority = %d, want %d", sections[0].Priority, layout.PriorityFor(layout.Context))
+	}
+	if sections[1].Compact != "" {
+		t.Errorf("duration has no compact form, got %q", sections[1].Compact)
+	}
+}
+
+func TestFitCompactsFormattedSegment(t *testing.T) {
+	input := types.Input{
+		ContextWindow: types.ContextWindow{
+			ContextWindowSize: 200000,
+			CurrentUsage:      types.CurrentUsage{InputTokens: 60000},
+		},
+	}
+	rc := newContext(input, types.Credentials{})
+	rc.Config.Formats = map[string]string{layout.Context: "<{{.Default}}>"}
+	sections := Build([]string{layout.Model, layout.Context}, rc)
+
+	full := layout.Assemble(sections, rc.Renderer)
+	if !strings.Contains(full, "<Ctx: [bar]") {
+		t.Fatalf("formatted line = %q, want the template around the full context", full)
+	}
+	got := layout.Assemble(layout.Fit(sections, rc.Renderer, layout.VisibleWidth(full)-1), rc.Renderer)
+	if want := "Opus 4.6  │  <Ctx: 30%>"; got != want {
+		t.Errorf("compacted line = %q, want %q", got, want)
+	}
+
+	// Without .Default the template renders the same either way, so the
+	// segment has nothing shorter to offer
+	rc = newContext(input, types.Credentials{})
+	rc.Config.Formats = map[string]string{layout.Context: "ctx {{.Percent}}%"}
+	if got := Build([]string{layout.Context}, rc)[0]; got.Compact != got.Text {
+		t.Errorf("compact = %q, want the formatted text %q", got.Compact, got.Text)
+	}
+}
+
+// iconRenderer draws every icon as its name in angle brackets.
+type iconRenderer struct{ mockRenderer }
+
+func (r *iconRenderer) Icon(name types.Icon) string { return "<" + string(name) + ">" }
+
+func TestIconsFromRenderer(t *testing.T) {
+	input := types.Input{Cost: types.Cost{TotalDurationMS: 120000}}
+
+	tests := []struct {
+		name    string
+		model   types.ModelInfo
+		segment string
+		want    string
+	}{
+		{"claude model", types.ModelInfo{ShortName: "Opus 4.6"}, layout.Model, "<model> Opus 4.6"},
+		{"local model", types.ModelInfo{ShortName: "🦙 Qwen3", IsLocal: true}, layout.Model, "<llama> Qwen3"},
+		{"duration", types.ModelInfo{ShortName: "Opus 4.6"}, layout.Duration, "<clock> 2m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := newContext(input, types.Credentials{})
+			rc.Model = tt.model
+			rc.Renderer = &iconRenderer{}
+			s, _ := Lookup(tt.segment)
+			if got := s.Render(rc); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.segment, got, tt.want)
+			}
+		})
+	}
+
+	// Emoji set: local models keep their llama, other icons are not drawn
+	rc := newContext(input, types.Credentials{})
+	rc.Model = types.ModelInfo{ShortName: "🦙 Qwen3", IsLocal: true}
+	s, _ := Lookup(layout.Model)
+	if got := s.Render(rc); got != "🦙 Qwen3" {
+		t.Errorf("model with emoji icons = %q, want %q", got, "🦙 Qwen3")
+	}
+}
+
+func TestRenderHistorySegment(t *testing.T) {
+	s, _ := Lookup(layout.History)
+	now := time.Now()
+
+	rc := newContext(types.Input{}, types.Credentials{})
+	rc.Value(DepRateLimit, func() any {
+		return ratelimit.State{Data: types.RateLimitData{FiveHourPercent: 50, FiveHourReset: now.Add(4*time.Hour + 10*time.Minute), SevenDayPercent: 30}}
+	})
+	rc.Value(DepHistory, func() any {
+		return []history.Point{{Time: now.Add(-time.Minute), FiveHour: 50, SevenDay: 30}}
+	})
+	if got := s.Render(rc); got != "5h ▅ 7d ▃" {
+		t.Errorf("history = %q, want %q", got, "5h ▅ 7d ▃")
+	}
+
+	unavailable := newContext(types.Input{}, types.Credentials{})
+	unavailable.Value(DepRateLimit, func() any { return ratelimit.State{Err: errors.New("unavailable")} })
+	if got := s.Render(unavailable); got != "" {
+		t.Errorf("history without rate limits = %q, want empty", got)
+	}
+}
+
+func TestRenderExtraSegment(t *testing.T) {
+	s, _ := Lookup(layout.Extra)
+	extra := func(e types.ExtraUsage) func() any {
+		return func() any { return ratelimit.State{Data: types.RateLimitData{Extra: e}} }
+	}
+
+	rc := newContext(types.Input{}, types.Credentials{OAuthToken: "token"})
+	rc.Value(DepRateLimit, extra(types.ExtraUsage{Enabled: true, Used: 12.5, Limit: 50}))
+	if got := s.Render(rc); !strings.HasPrefix(got, "💰 extra ") {
+		t.Errorf("extra = %q, want the cost icon and label", got)
+	}
+	if data, _ := extraData(rc).(*ExtraData); data == nil || data.Used != 12.5 || data.Limit != 50 || data.Percent != 25 {
+		t.Errorf("extra data = %+v, want 12.5 of 50 (25%%)", data)
+	}
+
+	off := newContext(types.Input{}, types.Credentials{OAuthToken: "token"})
+	off.Value(DepRateLimit, extra(types.ExtraUsage{}))
+	if got := s.Render(off); got != "" {
+		t.Errorf("extra when off = %q, want empty", got)
+	}
+	if data := extraData(off); data != nil {
+		t.Errorf("extra data when off = %+v, want nil", data)
+	}
+
+	apiKey := newContext(types.Input{}, types.Credentials{APIKey: "key"})
+	apiKey.Value(DepRateLimit, extra(types.ExtraUsage{Enabled: true, Used: 1}))
+	if got := s.Render(apiKey); got != "" {
+		t.Errorf("extra for API keys = %q, want empty", got)
+	}
+}
+
+func TestRenderAccountSegment(t *testing.T) {
+	s, _ := Lookup(layout.Account)
+
+	rc := newContext(types.Input{}, types.Credentials{OAuthToken: "token", AccountID: "0b1c", Email: "ben@acme.com"})
+	if got := s.Render(rc); got != "ben@acme" {
+		t.Errorf("account = %q, want %q", got, "ben@acme")
+	}
+	if data, _ := accountData(rc).(*AccountData); data == nil || data.Email != "ben@acme.com" || data.Key != rc.Credentials.AccountKey() {
+		t.Errorf("account data = %+v", data)
+	}
+
+	envToken := newContext(types.Input{}, types.Credentials{OAuthToken: "token"})
+	if got := s.Render(envToken); !strings.HasPrefix(got, "#") || len(got) != 7 {
+		t.Errorf("account without email = %q, want #<6 hex digits>", got)
+	}
+
+	apiKey := newContext(types.Input{}, types.Credentials{APIKey: "key"})
+	if got := s.Render(apiKey); got != "" {
+		t.Errorf("account for API keys = %q, want empty", got)
+	}
+	if data := accountData(apiKey); data != nil {
+		t.Errorf("account data for API keys = %+v, want nil", data)
+	}
+}
@@ -42,6 +42,11 @@ func (m *mockCache) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+
 func (m *mockCache) FileMTime(path string) (time.Time, error) {
 	return time.Now(), nil
 }
@@ -0,0 +1,42 @@
+package status
+
+import (
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Waybar is the output of a Waybar custom module with "return-type": "json".
+type Waybar struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip,omitempty"`
+	Class      string `json:"class"`                // "ok", "warn" or "critical"
+	Percentage *int   `json:"percentage,omitempty"` // Headline usage, for format-icons
+}
+
+// I3Block is the output of an i3blocks block with "format=json".
+type I3Block struct {
+	FullText  string `json:"full_text"`
+	ShortText string `json:"short_text,omitempty"`
+	Color     string `json:"color,omitempty"`
+	Urgent    bool   `json:"urgent,omitempty"`
+}
+
+// Waybar builds the Waybar module output around the rendered text.
+func (s Snapshot) Waybar(text string, r ports.Renderer) Waybar {
+	w := Waybar{Text: text, Tooltip: s.Tooltip(r), Class: string(s.Level())}
+	if pct, ok := s.Percentage(); ok {
+		w.Percentage = &pct
+	}
+	return w
+}
+
+// I3Block builds the i3blocks output around the rendered text.
+// color is the "#rrggbb" color for the snapshot's level ("" = bar default).
+func (s Snapshot) I3Block(text, color string, r ports.Renderer) I3Block {
+	return I3Block{
+		FullText:  text,
+		ShortText: s.Headline(r),
+		Color:     color,
+		Urgent:    s.Level() == types.RoleCritical,
+	}
+}
@@ -0,0 +1,217 @@
+package status
+
+import (
+	"math"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/ollama"
+)
+
+// Document is the output of --format json. Sections that do not apply are null,
+// so the set of keys stays stable for scripts and dashboards.
+type Document struct {
+	Model      *ModelJSON     `json:"model"`
+	Context    *ContextJSON   `json:"context"`
+	RateLimits *RateLimitJSON `json:"rate_limits"`
+	Pace       *PaceJSON      `json:"pace"`
+	Burn       *BurnJSON      `json:"burn"`
+	Cost       *CostJSON      `json:"cost"`
+	Agents     *AgentsJSON    `json:"agents"`
+	Ollama     *OllamaJSON    `json:"ollama"`
+}
+
+// ModelJSON is the resolved model.
+type ModelJSON struct {
+	Name           string  `json:"name"`
+	DefaultContext int     `json:"default_context"`
+	IsLocal        bool    `json:"is_local"`
+	CostWeight     float64 `json:"cost_weight"`
+}
+
+// ContextJSON is the context window usage.
+type ContextJSON struct {
+	PercentUsed  int  `json:"percent_used"`
+	TokensUsed   int  `json:"tokens_used"`
+	TokensTotal  int  `json:"tokens_total"`
+	IsInitial    bool `json:"is_initial"` // No usage reported yet
+	LinesAdded   int  `json:"lines_added"`
+	LinesRemoved int  `json:"lines_removed"`
+	DurationMin  int  `json:"duration_min"`
+}
+
+// RateLimitJSON is the subscription rate limit usage and where it came from.
+type RateLimitJSON struct {
+	Source     string            `json:"source"`      // "stdin", "api" or "cache"
+	AgeSeconds int               `json:"age_seconds"` // Time since the data was received
+	FiveHour   WindowJSON        `json:"five_hour"`
+	SevenDay   WindowJSON        `json:"seven_day"`   // Weekly window of the active model, like the 7d segment (e.g. 7d-opus)
+	Windows    []NamedWindowJSON `json:"windows"`     // Every window, including 5h and 7d
+	Extra      *ExtraUsageJSON   `json:"extra_usage"` // null when extra usage is off or unknown
+}
+
+// ExtraUsageJSON is the paid usage beyond the limits this month.
+type ExtraUsageJSON struct {
+	UsedUSD     float64 `json:"used_usd"`
+	LimitUSD    float64 `json:"limit_usd"` // 0 = no monthly limit
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// WindowJSON is the usage of one rate limit window.
+type WindowJSON struct {
+	UsedPercent     float64   `json:"used_percent"`
+	ResetsAt        time.Time `json:"resets_at"`
+	ResetsInSeconds int       `json:"resets_in_seconds"`
+}
+
+// NamedWindowJSON is a rate limit window with its name, e.g. "7d-opus".
+type NamedWindowJSON struct {
+	Name string `json:"name"`
+	WindowJSON
+}
+
+// PaceJSON is the usage pace per window (1.0 = on track to use exactly 100%).
+type PaceJSON struct {
+	FiveHour        float64    `json:"five_hour"`
+	FiveHourElapsed int        `json:"five_hour_elapsed_percent"`
+	SevenDay        float64    `json:"seven_day"`
+	SevenDayElapsed int        `json:"seven_day_elapsed_percent"`
+	HittingLimit    bool       `json:"hitting_limit"`
+	LimitAt         *time.Time `json:"limit_at"`        // null when not hitting the 5h limit
+	CostMultiplier  float64    `json:"cost_multiplier"` // Model cost weight applied to displayed pace and burn
+
+	SevenDayExhaustAt  *time.Time `json:"seven_day_exhaust_at"`          // null when the 7d limit lasts until the reset (or no forecast)
+	SevenDayConfidence string     `json:"seven_day_forecast_confidence"` // "none", "low", "medium" or "high"
+}
+
+// BurnJSON is the token burn rate.
+type BurnJSON struct {
+	LocalTPM       float64 `json:"local_tpm"`
+	GlobalTPM      float64 `json:"global_tpm"`
+	IsHighActivity bool    `json:"is_high_activity"`
+}
+
+// CostJSON is the API key cost tracking.
+type CostJSON struct {
+	SessionID  string  `json:"session_id"`
+	SessionUSD float64 `json:"session_usd"`
+	DailyUSD   float64 `json:"daily_usd"`
+	PerHourUSD float64 `json:"per_hour_usd"`
+	LocalTPM   int     `json:"local_tpm"`
+}
+
+// AgentsJSON is the number of running Claude Code processes.
+type AgentsJSON struct {
+	Total        int  `json:"total"`
+	HasSubagents bool `json:"has_subagents"`
+}
+
+// OllamaJSON is the local Ollama agent usage.
+type OllamaJSON struct {
+	Requests         int       `json:"requests"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	SavedUSD         float64   `json:"saved_usd"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Document converts the snapshot into the JSON document. now is used for
+// ages and countdowns.
+func (s Snapshot) Document(now time.Time) Document {
+	var d Document
+	if m := s.Model; m != nil {
+		d.Model = &ModelJSON{
+			Name:           m.ShortName,
+			DefaultContext: m.DefaultContext,
+			IsLocal:        m.IsLocal,
+			CostWeight:     m.CostWeight,
+		}
+	}
+	if c := s.Context; c != nil {
+		d.Context = &ContextJSON{
+			PercentUsed:  c.PercentUsed,
+			TokensUsed:   c.TokensUsed,
+			TokensTotal:  c.TokensTotal,
+			IsInitial:    c.IsInitial,
+			LinesAdded:   c.LinesAdded,
+			LinesRemoved: c.LinesRemoved,
+			DurationMin:  c.DurationMin,
+		}
+	}
+	if st := s.Rate; st != nil {
+		d.RateLimits = &RateLimitJSON{
+			Source:   st.Data.Source,
+			FiveHour: windowJSON(st.Data.FiveHourPercent, st.Data.FiveHourReset, now),
+			SevenDay: windowJSON(st.SevenDay().Percent, st.SevenDay().Reset, now),
+			Windows:  []NamedWindowJSON{},
+		}
+		for _, w := range st.Data.Windows {
+			d.RateLimits.Windows = append(d.RateLimits.Windows, NamedWindowJSON{Name: w.Name, WindowJSON: windowJSON(w.Percent, w.Reset, now)})
+		}
+		if extra := st.Data.Extra; extra.Enabled {
+			d.RateLimits.Extra = &ExtraUsageJSON{UsedUSD: extra.Used, LimitUSD: extra.Limit, UsedPercent: round2(extra.Percent())}
+		}
+		if !st.Data.FetchedAt.IsZero() {
+			d.RateLimits.AgeSeconds = seconds(now.Sub(st.Data.FetchedAt))
+		}
+		d.Pace = &PaceJSON{
+			FiveHour:        round2(st.Pace.FiveHourPace),
+			FiveHourElapsed: st.Pace.FiveHourTimePct,
+			SevenDay:        round2(st.Pace.SevenDayPace),
+			SevenDayElapsed: st.Pace.SevenDayTimePct,
+			HittingLimit:    st.Pace.HittingLimit,
+			CostMultiplier:  st.Norm.Mult,
+		}
+		if st.Pace.HittingLimit {
+			at := st.Pace.LimitAt
+			d.Pace.LimitAt = &at
+		}
+		d.Pace.SevenDayConfidence = s.Forecast.Confidence.String()
+		if !s.Forecast.ExhaustAt.IsZero() {
+			at := s.Forecast.ExhaustAt
+			d.Pace.SevenDayExhaustAt = &at
+		}
+		d.Burn = &BurnJSON{
+			LocalTPM:       math.Round(st.Burn.LocalTPM),
+			GlobalTPM:      math.Round(st.Burn.GlobalTPM),
+			IsHighActivity: st.Burn.IsHighActivity,
+		}
+	}
+	if st := s.Cost; st != nil {
+		d.Cost = &CostJSON{
+			SessionID:  st.Display.SessionID,
+			SessionUSD: st.Display.SessionCost,
+			DailyUSD:   st.Display.DailyCost,
+			PerHourUSD: st.Display.CostPerHour,
+			LocalTPM:   st.LocalTPM,
+		}
+	}
+	if a := s.Agents; a != nil {
+		d.Agents = &AgentsJSON{Total: a.Total, HasSubagents: a.HasSubagents}
+	}
+	if o := s.Ollama; o != nil {
+		d.Ollama = &OllamaJSON{
+			Requests:         o.Requests,
+			PromptTokens:     o.TotalPromptTokens,
+			CompletionTokens: o.TotalCompletionTokens,
+			SavedUSD:         ollama.Savings(o),
+			UpdatedAt:        time.Unix(o.LastUpdated, 0),
+		}
+	}
+	return d
+}
+
+func windowJSON(pct float64, reset, now time.Time) WindowJSON {
+	return WindowJSON{UsedPercent: pct, ResetsAt: reset, ResetsInSeconds: seconds(reset.Sub(now))}
+}
+
+// seconds rounds a duration down to whole seconds, clamped at zero.
+func seconds(d time.Duration) int {
+	if d < 0 {
+		return 0
+	}
+	return int(d / time.Second)
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,150 @@
+// Package status collects the computed data behind a statusline render for
+// output formats that need numbers rather than rendered text (status bars, JSON).
+package status
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Benniphx/claude-statusline/core/agents"
+	corecontext "github.com/Benniphx/claude-statusline/core/context"
+	"github.com/Benniphx/claude-statusline/core/cost"
+	"github.com/Benniphx/claude-statusline/core/history"
+	"github.com/Benniphx/claude-statusline/core/ollama"
+	"github.com/Benniphx/claude-statusline/core/ports"
+	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/segment"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// Snapshot holds the computed data of one render. Sections that do not apply
+// (no Claude Code input, other account mode, no rate data, no Ollama stats) are nil.
+type Snapshot struct {
+	Model    *types.ModelInfo
+	Context  *types.ContextDisplay
+	Rate     *ratelimit.State
+	Forecast history.Forecast // Weekly limit projected from the history (with Rate)
+	Cost     *cost.State
+	Agents   *agents.AgentInfo
+	Ollama   *ollama.Stats
+
+	Thresholds types.Thresholds // Color levels (zero = defaults)
+	Locale     types.Locale     // Clock and number format
+}
+
+// Collect gathers the snapshot through the segment providers, so values already
+// computed for the rendered segments are reused. withInput is false when the
+// statusline runs without Claude Code input (e.g. from a status bar).
+func Collect(rc *ports.RenderContext, withInput bool) Snapshot {
+	s := Snapshot{Thresholds: rc.Config.Thresholds, Locale: rc.Config.Locale}
+	if withInput {
+		model := rc.Model
+		display := segment.Get(rc, segment.DepContext).(types.ContextDisplay)
+		info := segment.Get(rc, segment.DepAgents).(agents.AgentInfo)
+		s.Model, s.Context, s.Agents = &model, &display, &info
+	}
+	if rc.Credentials.HasOAuth() {
+		if st := segment.Get(rc, segment.DepRateLimit).(ratelimit.State); st.Err == nil {
+			s.Rate = &st
+			s.Forecast = segment.Get(rc, segment.DepForecast).(history.Forecast)
+		}
+	} else if withInput {
+		st := segment.Get(rc, segment.DepCost).(cost.State)
+		s.Cost = &st
+	}
+	s.Ollama = segment.Get(rc, segment.DepOllama).(*ollama.Stats)
+	return s
+}
+
+// Percentage is the headline usage: the 5h window for subscriptions,
+// context window usage otherwise. ok is false when neither is known.
+func (s Snapshot) Percentage() (pct int, ok bool) {
+	switch {
+	case s.Rate != nil:
+		return int(math.Round(s.Rate.Data.FiveHourPercent)), true
+	case s.Context != nil && !s.Context.IsInitial:
+		return s.Context.PercentUsed, true
+	}
+	return 0, false
+}
+
+// Level is the most severe role across context and rate limit usage,
+// each by its own threshold.
+func (s Snapshot) Level() types.Role {
+	th := s.thresholds()
+	level := types.RoleOK
+	if s.Context != nil && !s.Context.IsInitial {
+		level = worse(level, th.Context.Role(float64(s.Context.PercentUsed)))
+	}
+	if s.Rate != nil {
+		level = worse(level, th.FiveHour.Role(s.Rate.Data.FiveHourPercent))
+		level = worse(level, th.SevenDay.Role(s.Rate.SevenDay().Percent))
+	}
+	return level
+}
+
+// thresholds returns the snapshot's thresholds, or the defaults when unset.
+func (s Snapshot) thresholds() types.Thresholds {
+	if s.Thresholds == (types.Thresholds{}) {
+		return types.DefaultThresholds()
+	}
+	return s.Thresholds
+}
+
+var severity = map[types.Role]int{types.RoleOK: 0, types.RoleWarn: 1, types.RoleCritical: 2}
+
+// worse returns the more severe of two level roles.
+func worse(a, b types.Role) types.Role {
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
+
+// Headline is the short form of the headline usage: "5h: 46%" or "Ctx: 30%".
+func (s Snapshot) Headline(r ports.Renderer) string {
+	switch {
+	case s.Rate != nil:
+		return fmt.Sprintf("5h: %d%%", int(math.Round(s.Rate.Data.FiveHourPercent)))
+	case s.Context != nil:
+		cfg := types.DefaultConfig()
+		cfg.Thresholds = s.thresholds()
+		cfg.Locale = s.Locale
+		return corecontext.RenderCompact(*s.Context, cfg, r)
+	}
+	return ""
+}
+
+// Tooltip describes every known metric, one per line.
+func (s Snapshot) Tooltip(r ports.Renderer) string {
+	var lines []string
+	if s.Model != nil {
+		lines = append(lines, "Model: "+s.Model.ShortName)
+	}
+	if c := s.Context; c != nil {
+		lines = append(lines, fmt.Sprintf("Context: %d%% (%s/%s)",
+			c.PercentUsed, r.FormatTokensF(c.TokensUsed), r.FormatTokens(c.TokensTotal)))
+	}
+	if st := s.Rate; st != nil {
+		lines = append(lines,
+			s.window("5h", st.Data.FiveHourPercent, st.Pace.FiveHourPace*st.Norm.Mult, s.Locale.Time(st.Data.FiveHourReset)),
+			s.window(st.SevenDay().Name, st.SevenDay().Percent, st.Pace.SevenDayPace*st.Norm.Mult, s.Locale.DayTime(st.SevenDay().Reset)))
+		if tpm := int(math.Round(st.Burn.LocalTPM * st.Norm.Mult)); tpm > 0 {
+			lines = append(lines, fmt.Sprintf("Burn: %s%s t/m", st.Norm.Prefix, r.FormatTokensF(tpm)))
+		}
+	}
+	if st := s.Cost; st != nil {
+		lines = append(lines, fmt.Sprintf("Session: %s · Today: %s · %s/h",
+			r.FormatCost(st.Display.SessionCost), r.FormatCost(st.Display.DailyCost), r.FormatCost(st.Display.CostPerHour)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s Snapshot) window(name string, pct, pace float64, reset string) string {
+	line := fmt.Sprintf("%s: %d%%", name, int(math.Round(pct)))
+	if pace > 0 {
+		line += " · pace " + s.Locale.Number(pace, 1) + "x"
+	}
+	return line + " · resets " + reset
+}
@@ -0,0 +1,187 @@
+package status
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/agents"
+	"github.com/Benniphx/claude-statusline/core/cost"
+	"github.com/Benniphx/claude-statusline/core/ollama"
+	"github.com/Benniphx/claude-statusline/core/ratelimit"
+	"github.com/Benniphx/claude-statusline/core/types"
+)
+
+// mockRenderer implements ports.Renderer without escape codes.
+type mockRenderer struct{}
+
+func (m *mockRenderer) Style(text string, role types.Role) string      { return text }
+func (m *mockRenderer) Dim(text string) string                         { return text }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string {
+	return "[split]"
+}
+func (m *mockRenderer) FormatTokens(n int) string   { return "200K" }
+func (m *mockRenderer) FormatTokensF(n int) string  { return "60.0K" }
+func (m *mockRenderer) FormatCost(f float64) string { return "$0.80" }
+func (m *mockRenderer) Icon(name types.Icon) string { return types.EmojiIcons[name] }
+
+func rateState(five, seven float64) *ratelimit.State {
+	reset := time.Date(2026, 3, 5, 14, 30, 0, 0, time.Local)
+	return &ratelimit.State{
+		Data: types.RateLimitData{
+			FiveHourPercent: five,
+			FiveHourReset:   reset,
+			SevenDayPercent: seven,
+			SevenDayReset:   reset.Add(24 * time.Hour),
+		},
+		Pace: types.PaceInfo{FiveHourPace: 1.2},
+		Burn: types.BurnInfo{LocalTPM: 5000},
+		Norm: types.CostNorm{Mult: 1.0},
+	}
+}
+
+// opusWeekly makes the model's own 7d window, at pct, drive the 7d figures.
+func opusWeekly(st *ratelimit.State, pct float64) *ratelimit.State {
+	st.Weekly = types.RateWindow{Name: "7d-opus", Percent: pct, Reset: st.Data.SevenDayReset}
+	st.Data.Windows = append(st.Data.Windows, st.Weekly)
+	return st
+}
+
+func TestPercentageAndLevel(t *testing.T) {
+	ctx := &types.ContextDisplay{PercentUsed: 30, TokensUsed: 60000, TokensTotal: 200000}
+	relaxed7d := types.DefaultThresholds()
+	relaxed7d.SevenDay = types.Threshold{Warn: 80, Crit: 95}
+
+	tests := []struct {
+		name      string
+		snap      Snapshot
+		wantPct   int
+		wantOK    bool
+		wantLevel types.Role
+	}{
+		{"nothing known", Snapshot{}, 0, false, types.RoleOK},
+		{"context only", Snapshot{Context: ctx}, 30, true, types.RoleOK},
+		{"initial context", Snapshot{Context: &types.ContextDisplay{IsInitial: true}}, 0, false, types.RoleOK},
+		{"rate wins headline", Snapshot{Context: ctx, Rate: rateState(46, 20)}, 46, true, types.RoleOK},
+		{"7d drives level", Snapshot{Context: ctx, Rate: rateState(46, 85)}, 46, true, types.RoleCritical},
+		{"context drives level", Snapshot{Context: &types.ContextDisplay{PercentUsed: 60}}, 60, true, types.RoleWarn},
+		{"per-metric thresholds", Snapshot{Context: ctx, Rate: rateState(46, 85), Thresholds: relaxed7d}, 46, true, types.RoleWarn},
+		{"model weekly window drives level", Snapshot{Context: ctx, Rate: opusWeekly(rateState(46, 20), 90)}, 46, true, types.RoleCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pct, ok := tt.snap.Percentage()
+			if pct != tt.wantPct || ok != tt.wantOK {
+				t.Errorf("Percentage = %d, %v; want %d, %v", pct, ok, tt.wantPct, tt.wantOK)
+			}
+			if got := tt.snap.Level(); got != tt.wantLevel {
+				t.Errorf("Level = %q, want %q", got, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestTooltip(t *testing.T) {
+	r := &mockRenderer{}
+	snap := Snapshot{
+		Model:   &types.ModelInfo{ShortName: "Opus 4.6"},
+		Context: &types.ContextDisplay{PercentUsed: 30},
+		Rate:    rateState(46, 27),
+	}
+
+	want := strings.Join([]string{
+		"Model: Opus 4.6",
+		"Context: 30% (60.0K/200K)",
+		"5h: 46% · pace 1.2x · resets 14:30",
+		"7d: 27% · resets Fri 14:30",
+		"Burn: 60.0K t/m",
+	}, "\n")
+	if got := snap.Tooltip(r); got != want {
+		t.Errorf("Tooltip =\n%s\nwant\n%s", got, want)
+	}
+
+	costSnap := Snapshot{Cost: &cost.State{}}
+	if got := costSnap.Tooltip(r); got != "Session: $0.80 · Today: $0.80 · $0.80/h" {
+		t.Errorf("cost tooltip = %q", got)
+	}
+}
+
+func TestWaybarJSON(t *testing.T) {
+	r := &mockRenderer{}
+
+	data, _ := json.Marshal(Snapshot{Rate: rateState(85, 10)}.Waybar("5h: 85%", r))
+	var got map[string]any
+	json.Unmarshal(data, &got)
+	if got["text"] != "5h: 85%" || got["class"] != "critical" || got["percentage"] != 85.0 {
+		t.Errorf("waybar = %s", data)
+	}
+
+	// Unknown percentage is omitted rather than reported as 0
+	data, _ = json.Marshal(Snapshot{}.Waybar("", r))
+	if strings.Contains(string(data), "percentage") {
+		t.Errorf("waybar without data should omit percentage: %s", data)
+	}
+}
+
+func TestI3BlockJSON(t *testing.T) {
+	r := &mockRenderer{}
+
+	block := Snapshot{Rate: rateState(90, 10)}.I3Block("full", "#cd0000", r)
+	want := I3Block{FullText: "full", ShortText: "5h: 90%", Color: "#cd0000", Urgent: true}
+	if block != want {
+		t.Errorf("I3Block = %+v, want %+v", block, want)
+	}
+
+	data, _ := json.Marshal(Snapshot{Context: &types.ContextDisplay{PercentUsed: 30}}.I3Block("full", "", r))
+	if got := string(data); got != `{"full_text":"full","short_text":"Ctx: 30%"}` {
+		t.Errorf("i3blocks JSON = %s", got)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 30, 0, 0, time.Local)
+	rate := rateState(46, 27)
+	rate.Data.Source = types.RateSourceCache
+	rate.Data.FetchedAt = now.Add(-90 * time.Second)
+	rate.Pace.HittingLimit = true
+	rate.Pace.LimitAt = now.Add(time.Hour)
+
+	snap := Snapshot{
+		Model:  &types.ModelInfo{ShortName: "Opus 4.6", DefaultContext: 200000, CostWeight: 5},
+		Rate:   rate,
+		Agents: &agents.AgentInfo{Total: 3, HasSubagents: true},
+		Ollama: &ollama.Stats{Requests: 4, TotalPromptTokens: 2_000_000},
+	}
+	doc := snap.Document(now)
+
+	if doc.RateLimits.Source != "cache" || doc.RateLimits.AgeSeconds != 90 {
+		t.Errorf("rate limits source/age = %q/%d, want cache/90", doc.RateLimits.Source, doc.RateLimits.AgeSeconds)
+	}
+	if got := doc.RateLimits.FiveHour.ResetsInSeconds; got != 7200 {
+		t.Errorf("five_hour resets_in_seconds = %d, want 7200", got)
+	}
+	if doc.Pace.FiveHour != 1.2 || doc.Pace.LimitAt == nil || !doc.Pace.LimitAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("pace = %+v", doc.Pace)
+	}
+	if doc.Pace.SevenDayExhaustAt != nil || doc.Pace.SevenDayConfidence != "none" {
+		t.Errorf("7d forecast without history = %v/%q, want null/none", doc.Pace.SevenDayExhaustAt, doc.Pace.SevenDayConfidence)
+	}
+	if doc.Burn.LocalTPM != 5000 || doc.Agents.Total != 3 || doc.Ollama.SavedUSD != 0.5 {
+		t.Errorf("burn/agents/ollama = %+v %+v %+v", doc.Burn, doc.Agents, doc.Ollama)
+	}
+
+	// The weekly window is the active model's, like in the 7d segment
+	weekly := Snapshot{Rate: opusWeekly(rateState(46, 27), 62)}.Document(now)
+	if got := weekly.RateLimits.SevenDay.UsedPercent; got != 62 {
+		t.Errorf("seven_day used_percent = %v, want 62 (7d-opus)", got)
+	}
+
+	// Sections that do not apply are null, not omitted
+	data, _ := json.Marshal(Snapshot{}.Document(now))
+	want := `{"model":null,"context":null,"rate_limits":null,"pace":null,"burn":null,"cost":null,"agents":null,"ollama":null}`
+	if string(data) != want {
+		t.Errorf("empty document = %s, want %s", data, want)
+	}
+}
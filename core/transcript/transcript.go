@@ -0,0 +1,245 @@
+// Package transcript reads the Claude Code session transcript (JSONL)
+// incrementally, accumulating token usage, models and tool calls.
+package transcript
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Benniphx/claude-statusline/core/ports"
+)
+
+// Usage holds the token counts of one or more API responses.
+type Usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// Add returns the sum of u and o.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:              u.InputTokens + o.InputTokens,
+		OutputTokens:             u.OutputTokens + o.OutputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens + o.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens + o.CacheReadInputTokens,
+	}
+}
+
+// Sub returns u minus o.
+func (u Usage) Sub(o Usage) Usage {
+	return Usage{
+		InputTokens:              u.InputTokens - o.InputTokens,
+		OutputTokens:             u.OutputTokens - o.OutputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens - o.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens - o.CacheReadInputTokens,
+	}
+}
+
+// Context returns the tokens sent as context: input plus cache creation and reads.
+func (u Usage) Context() int {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// Message is one assistant API response.
+type Message struct {
+	ID        string    `json:"id"`
+	Model     string    `json:"model"`
+	Usage     Usage     `json:"usage"`
+	Tools     []string  `json:"tools,omitempty"` // Names of the tools called, in order
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// State is the accumulated transcript data of a session, persisted between renders.
+type State struct {
+	Offset    int64          `json:"offset"`     // Bytes of the transcript consumed
+	Total     Usage          `json:"total"`      // All assistant messages
+	Messages  int            `json:"messages"`   // Assistant messages
+	Tools     map[string]int `json:"tools"`      // Tool calls by name
+	Turn      Usage          `json:"turn"`       // Since the last user prompt
+	TurnTools int            `json:"turn_tools"` // Tool calls since the last user prompt
+	Last      Message        `json:"last"`       // Most recent assistant message
+}
+
+// Model returns the model of the most recent message.
+func (s State) Model() string {
+	return s.Last.Model
+}
+
+// line is the subset of a transcript line we read.
+type line struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	IsMeta    bool      `json:"isMeta"`
+	Message   struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *Usage          `json:"usage"`
+	} `json:"message"`
+}
+
+type contentBlock struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Read consumes the lines appended to the transcript at path since the last
+// read for the session, and returns the updated state with the messages read.
+// The state is kept in cacheDir, keyed by sessionID (or the path without one).
+// A transcript shorter than the saved offset (replaced or truncated) is re-read.
+func Read(path, sessionID, cacheDir string, store ports.CacheStore) (State, []Message, error) {
+	statePath := fmt.Sprintf("%s/claude_transcript_%s.json", cacheDir, stateKey(path, sessionID))
+	st := loadState(statePath, store)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return st, nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() < st.Offset {
+		st = State{}
+	}
+	if _, err := f.Seek(st.Offset, io.SeekStart); err != nil {
+		return st, nil, err
+	}
+
+	start := st.Offset
+	msgs, err := st.consume(bufio.NewReader(f))
+	if err != nil {
+		return st, msgs, err
+	}
+	if st.Offset != start {
+		if raw, err := json.Marshal(st); err == nil {
+			store.AtomicWrite(statePath, raw)
+		}
+	}
+	return st, msgs, nil
+}
+
+// consume applies complete lines from r, advancing the offset past each.
+// A trailing line without newline is still being written and is left for the next read.
+// Responses streamed as several lines with the same message ID count once.
+func (s *State) consume(r *bufio.Reader) ([]Message, error) {
+	var msgs []Message
+	for {
+		raw, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return msgs, nil
+		}
+		if err != nil {
+			return msgs, err
+		}
+		s.Offset += int64(len(raw))
+
+		var l line
+		if json.Unmarshal(raw, &l) != nil {
+			continue
+		}
+		switch {
+		case l.Type == "user" && !l.IsMeta && isPrompt(l.Message.Content):
+			s.Turn, s.TurnTools = Usage{}, 0
+		case l.Type == "assistant" && l.Message.Usage != nil:
+			tools := toolNames(l.Message.Content)
+			msg := Message{
+				ID:        l.Message.ID,
+				Model:     l.Message.Model,
+				Usage:     *l.Message.Usage,
+				Tools:     tools,
+				Timestamp: l.Timestamp,
+			}
+			if msg.ID != "" && msg.ID == s.Last.ID {
+				// Another content block of the same response: replace its usage
+				s.Total, s.Turn = s.Total.Sub(s.Last.Usage), s.Turn.Sub(s.Last.Usage)
+				s.Messages--
+				msg.Tools = append(append([]string(nil), s.Last.Tools...), tools...)
+				if n := len(msgs); n > 0 && msgs[n-1].ID == msg.ID {
+					msgs = msgs[:n-1]
+				}
+			}
+			s.Total, s.Turn = s.Total.Add(msg.Usage), s.Turn.Add(msg.Usage)
+			s.Messages++
+			s.countTools(tools)
+			s.Last = msg
+			msgs = append(msgs, msg)
+		}
+	}
+}
+
+// countTools adds newly seen tool calls to the per-name and per-turn counts.
+func (s *State) countTools(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	if s.Tools == nil {
+		s.Tools = map[string]int{}
+	}
+	for _, name := range names {
+		s.Tools[name]++
+	}
+	s.TurnTools += len(names)
+}
+
+// isPrompt reports whether user content is a prompt typed by the user,
+// as opposed to tool results (an array of tool_result blocks).
+func isPrompt(content json.RawMessage) bool {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return true
+	}
+	var blocks []contentBlock
+	if json.Unmarshal(content, &blocks) != nil {
+		return false
+	}
+	for _, b := range blocks {
+		if b.Type == "tool_result" {
+			return false
+		}
+	}
+	return len(blocks) > 0
+}
+
+// toolNames returns the names of the tool_use blocks in assistant content.
+func toolNames(content json.RawMessage) []string {
+	var blocks []contentBlock
+	if json.Unmarshal(content, &blocks) != nil {
+		return nil
+	}
+	var names []string
+	for _, b := range blocks {
+		if b.Type == "tool_use" && b.Name != "" {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+// stateKey names the state file: the session ID when usable in a file name,
+// else a hash of the transcript path.
+func stateKey(path, sessionID string) string {
+	if sessionID != "" && !strings.ContainsAny(sessionID, `/\`) && sessionID != "." && sessionID != ".." {
+		return sessionID
+	}
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:8])
+}
+
+func loadState(path string, store ports.CacheStore) State {
+	var st State
+	if data, err := store.ReadFile(path); err == nil {
+		if json.Unmarshal(data, &st) != nil {
+			return State{}
+		}
+	}
+	return st
+}
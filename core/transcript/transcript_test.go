@@ -0,0 +1,177 @@
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockCache implements ports.CacheStore for testing.
+type mockCache struct {
+	files map[string][]byte
+}
+
+func newMockCache() *mockCache {
+	return &mockCache{files: make(map[string][]byte)}
+}
+
+func (m *mockCache) AtomicWrite(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) ReadIfFresh(path string, ttl time.Duration) ([]byte, bool) { return nil, false }
+func (m *mockCache) ReadFile(path string) ([]byte, error) {
+	data, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return data, nil
+}
+func (m *mockCache) WriteFile(path string, data []byte) error {
+	m.files[path] = data
+	return nil
+}
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+func (m *mockCache) FileMTime(path string) (time.Time, error) { return time.Now(), nil }
+func (m *mockCache) CleanOld(dir, pattern, keep string) error { return nil }
+
+func prompt(text string) string {
+	return fmt.Sprintf(`{"type":"user","message":{"role":"user","content":%q}}`, text) + "\n"
+}
+
+func toolResult() string {
+	return `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}` + "\n"
+}
+
+// assistant is one content block of a response; usage repeats across blocks.
+func assistant(id, block string, in, out, cacheWrite, cacheRead int) string {
+	return fmt.Sprintf(`{"type":"assistant","timestamp":"2026-03-05T14:30:00Z","message":{"id":%q,"model":"claude-sonnet-4-5","content":[%s],"usage":{"input_tokens":%d,"output_tokens":%d,"cache_creation_input_tokens":%d,"cache_read_input_tokens":%d}}}`,
+		id, block, in, out, cacheWrite, cacheRead) + "\n"
+}
+
+func toolUse(name string) string {
+	return fmt.Sprintf(`{"type":"tool_use","id":"t1","name":%q,"input":{}}`, name)
+}
+
+const text = `{"type":"text","text":"hi"}`
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	store := newMockCache()
+
+	appendFile(t, path, prompt("fix the bug")+
+		assistant("msg_1", text, 10, 50, 1000, 0)+
+		assistant("msg_1", toolUse("Read"), 10, 80, 1000, 0)+ // same response, final output count
+		toolResult()+
+		assistant("msg_2", toolUse("Edit"), 5, 20, 200, 1000))
+
+	st, msgs, err := Read(path, "s1", dir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || st.Messages != 2 {
+		t.Fatalf("read %d messages (state %d), want 2", len(msgs), st.Messages)
+	}
+	if msgs[0].Usage.OutputTokens != 80 || strings.Join(msgs[0].Tools, ",") != "Read" {
+		t.Errorf("msg_1 = %+v, want output 80 and tool Read", msgs[0])
+	}
+	want := Usage{InputTokens: 15, OutputTokens: 100, CacheCreationInputTokens: 1200, CacheReadInputTokens: 1000}
+	if st.Total != want || st.Turn != want {
+		t.Errorf("Total = %+v, Turn = %+v, want %+v", st.Total, st.Turn, want)
+	}
+	if st.Tools["Read"] != 1 || st.Tools["Edit"] != 1 || st.TurnTools != 2 {
+		t.Errorf("Tools = %v, TurnTools = %d", st.Tools, st.TurnTools)
+	}
+	if st.Model() != "claude-sonnet-4-5" || st.Last.ID != "msg_2" {
+		t.Errorf("Last = %+v", st.Last)
+	}
+
+	// Nothing appended: no messages, same state
+	again, msgs, _ := Read(path, "s1", dir, store)
+	if len(msgs) != 0 || again.Total != st.Total {
+		t.Errorf("re-read returned %d messages, total %+v", len(msgs), again.Total)
+	}
+
+	// A new prompt starts a new turn; a partial line waits for its newline
+	partial := assistant("msg_3", text, 1, 7, 0, 1200)
+	appendFile(t, path, prompt("next")+partial[:20])
+	st, msgs, _ = Read(path, "s1", dir, store)
+	if len(msgs) != 0 || st.Turn != (Usage{}) || st.TurnTools != 0 {
+		t.Errorf("after prompt: %d messages, turn %+v, turn tools %d", len(msgs), st.Turn, st.TurnTools)
+	}
+	appendFile(t, path, partial[20:])
+	st, msgs, _ = Read(path, "s1", dir, store)
+	if len(msgs) != 1 || st.Turn.OutputTokens != 7 || st.Total.OutputTokens != 107 || st.Messages != 3 {
+		t.Errorf("after completing line: %d messages, turn %+v, total %+v", len(msgs), st.Turn, st.Total)
+	}
+
+	// A later block of the last response, read separately, still counts once
+	appendFile(t, path, assistant("msg_3", toolUse("Bash"), 1, 9, 0, 1200))
+	st, msgs, _ = Read(path, "s1", dir, store)
+	if len(msgs) != 1 || st.Messages != 3 || st.Total.OutputTokens != 109 || st.Tools["Bash"] != 1 {
+		t.Errorf("split response: %d messages, state %d, total %+v, tools %v", len(msgs), st.Messages, st.Total, st.Tools)
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	store := newMockCache()
+
+	appendFile(t, path, prompt("a")+assistant("msg_1", text, 1, 100, 0, 0)+assistant("msg_2", text, 1, 100, 0, 0))
+	Read(path, "s1", dir, store)
+
+	// Replaced by a shorter transcript: read from the start again
+	os.WriteFile(path, []byte(assistant("msg_9", text, 1, 5, 0, 0)), 0o644)
+	st, msgs, _ := Read(path, "s1", dir, store)
+	if len(msgs) != 1 || st.Total.OutputTokens != 5 || st.Messages != 1 {
+		t.Errorf("after truncation: %d messages, total %+v", len(msgs), st.Total)
+	}
+}
+
+func TestReadSessionsIsolated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	store := newMockCache()
+	appendFile(t, path, assistant("msg_1", text, 1, 10, 0, 0))
+
+	Read(path, "s1", dir, store)
+	_, msgs, _ := Read(path, "s2", dir, store)
+	if len(msgs) != 1 {
+		t.Errorf("second session read %d messages, want its own offset", len(msgs))
+	}
+
+	if _, _, err := Read(filepath.Join(dir, "missing.jsonl"), "s3", dir, store); err == nil {
+		t.Error("missing transcript should return an error")
+	}
+}
+
+func TestStateKey(t *testing.T) {
+	if got := stateKey("/p/a.jsonl", "abc-123"); got != "abc-123" {
+		t.Errorf("stateKey = %q, want the session ID", got)
+	}
+	for _, id := range []string{"", "../x", `a\b`, ".."} {
+		if got := stateKey("/p/a.jsonl", id); got == id || strings.ContainsAny(got, `/\.`) {
+			t.Errorf("stateKey(%q) = %q, want a path hash", id, got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+)
+
+// AccountKey returns a short hash identifying the OAuth account, for
+// per-account cache files: of the account ID when known, else of the email,
+// so that refreshed tokens keep their key. Only a token without a known
+// account (e.g. from the environment) is hashed itself. It is "" without OAuth.
+func (c Credentials) AccountKey() string {
+	if c.OAuthToken == "" {
+		return ""
+	}
+	id := c.AccountID
+	if id == "" {
+		id = c.Email
+	}
+	if id == "" {
+		id = c.OAuthToken
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:4])
+}
+
+// AccountLabel returns a short name for the OAuth account: the email without
+// its top-level domain ("ben@acme" for ben@acme.com), or "#" and the start of
+// the account key when the email is unknown. It is "" without OAuth.
+func (c Credentials) AccountLabel() string {
+	if user, domain, ok := strings.Cut(c.Email, "@"); ok {
+		if name, _, found := strings.Cut(domain, "."); found && name != "" {
+			domain = name
+		}
+		return user + "@" + domain
+	}
+	if key := c.AccountKey(); key != "" {
+		return "#" + key[:6]
+	}
+	return ""
+}
+
+// AccountFile inserts an account key before the extension of a cache file
+// name: "claude_rate_limit_cache.json" → "claude_rate_limit_cache_1a2b3c4d.json".
+// Without a key the name is unchanged.
+func AccountFile(name, key string) string {
+	if key == "" {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "_" + key + ext
+}
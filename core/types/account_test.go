@@ -0,0 +1,60 @@
+package types
+
+import "testing"
+
+func TestAccountKey(t *testing.T) {
+	work := Credentials{OAuthToken: "token-a", AccountID: "acct-1"}
+	refreshed := Credentials{OAuthToken: "token-b", AccountID: "acct-1"}
+	personal := Credentials{OAuthToken: "token-a", AccountID: "acct-2"}
+
+	if work.AccountKey() != refreshed.AccountKey() {
+		t.Error("a refreshed token of the same account should keep its key")
+	}
+	if work.AccountKey() == personal.AccountKey() {
+		t.Error("different accounts should get different keys")
+	}
+	byEmail := Credentials{OAuthToken: "token-a", Email: "ben@acme.com"}
+	if byEmail.AccountKey() != (Credentials{OAuthToken: "token-b", Email: "ben@acme.com"}).AccountKey() {
+		t.Error("without an account ID, a refreshed token should keep the key of its email")
+	}
+	if key := (Credentials{OAuthToken: "token-a"}).AccountKey(); len(key) != 8 || key == work.AccountKey() || key == byEmail.AccountKey() {
+		t.Errorf("token-only key = %q, want 8 hex digits of the token hash", key)
+	}
+	if key := (Credentials{APIKey: "sk-ant"}).AccountKey(); key != "" {
+		t.Errorf("API key account key = %q, want empty", key)
+	}
+}
+
+func TestAccountLabel(t *testing.T) {
+	key := Credentials{OAuthToken: "token"}.AccountKey()
+	tests := []struct {
+		creds Credentials
+		want  string
+	}{
+		{Credentials{OAuthToken: "token", Email: "ben@acme.com"}, "ben@acme"},
+		{Credentials{OAuthToken: "token", Email: "ben@mail.example.co.uk"}, "ben@mail"},
+		{Credentials{OAuthToken: "token", Email: "ben@localhost"}, "ben@localhost"},
+		{Credentials{OAuthToken: "token"}, "#" + key[:6]},
+		{Credentials{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.creds.AccountLabel(); got != tt.want {
+			t.Errorf("AccountLabel(%+v) = %q, want %q", tt.creds, got, tt.want)
+		}
+	}
+}
+
+func TestAccountFile(t *testing.T) {
+	tests := []struct {
+		name, key, want string
+	}{
+		{"claude_rate_limit_cache.json", "1a2b3c4d", "claude_rate_limit_cache_1a2b3c4d.json"},
+		{"claude_rate_history.txt", "1a2b3c4d", "claude_rate_history_1a2b3c4d.txt"},
+		{"claude_global_burn.json", "", "claude_global_burn.json"},
+	}
+	for _, tt := range tests {
+		if got := AccountFile(tt.name, tt.key); got != tt.want {
+			t.Errorf("AccountFile(%q, %q) = %q, want %q", tt.name, tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale controls how times and numbers are formatted.
+// The zero value formats like the original output: 24h clock, "." decimals,
+// no grouping and "K" suffixes.
+type Locale struct {
+	Clock     string // "24h" (default) or "12h"
+	Decimal   string // Decimal separator: "." (default) or ","
+	Thousands string // Thousands separator, "" = no grouping
+	Suffix    string // Token suffixes: "K" (default), "k", "K/M" or "k/m"
+}
+
+// Suffixes lists the supported token suffix styles.
+var Suffixes = []string{"K", "k", "K/M", "k/m"}
+
+// Time formats a time of day: "14:30" or "2:30pm".
+func (l Locale) Time(t time.Time) string {
+	if l.Clock == "12h" {
+		return t.Format("3:04pm")
+	}
+	return t.Format("15:04")
+}
+
+// DayTime formats a weekday and time of day: "Thu 14:30" or "Thu 2:30pm".
+func (l Locale) DayTime(t time.Time) string {
+	return t.Format("Mon") + " " + l.Time(t)
+}
+
+// Number formats f with the given number of decimals, grouping the integer part.
+func (l Locale) Number(f float64, decimals int) string {
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+	if l.Thousands != "" {
+		whole = group(whole, l.Thousands)
+	}
+	if frac == "" {
+		return sign + whole
+	}
+	decimal := l.Decimal
+	if decimal == "" {
+		decimal = "."
+	}
+	return sign + whole + decimal + frac
+}
+
+// Tokens formats a token count: plain below 1000, else with a thousands
+// (and, for "K/M" and "k/m", millions) suffix and the given decimals.
+// Counts that round up to 1000K are shown in millions: 999999 is "1.0M".
+func (l Locale) Tokens(n, decimals int) string {
+	if n < 1000 {
+		return l.Number(float64(n), 0)
+	}
+	suffix := l.Suffix
+	if suffix == "" {
+		suffix = "K"
+	}
+	thousands, millions, hasMillions := strings.Cut(suffix, "/")
+	k := float64(n) / 1000
+	if hasMillions && rounded(k, decimals) >= 1000 {
+		return l.Number(k/1000, decimals) + millions
+	}
+	return l.Number(k, decimals) + thousands
+}
+
+// rounded returns f rounded to decimals, as Number shows it.
+func rounded(f float64, decimals int) float64 {
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'f', decimals, 64), 64)
+	return r
+}
+
+// Cost formats a USD amount: "$1.20".
+func (l Locale) Cost(f float64) string {
+	return "$" + l.Number(f, 2)
+}
+
+// group inserts sep between groups of three digits.
+func group(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleTime(t *testing.T) {
+	at := time.Date(2026, 3, 5, 14, 30, 0, 0, time.Local)
+	morning := time.Date(2026, 3, 5, 9, 5, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		locale Locale
+		t      time.Time
+		time   string
+		day    string
+	}{
+		{"default", Locale{}, at, "14:30", "Thu 14:30"},
+		{"24h", Locale{Clock: "24h"}, morning, "09:05", "Thu 09:05"},
+		{"12h afternoon", Locale{Clock: "12h"}, at, "2:30pm", "Thu 2:30pm"},
+		{"12h morning", Locale{Clock: "12h"}, morning, "9:05am", "Thu 9:05am"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.locale.Time(tt.t); got != tt.time {
+				t.Errorf("Time() = %q, want %q", got, tt.time)
+			}
+			if got := tt.locale.DayTime(tt.t); got != tt.day {
+				t.Errorf("DayTime() = %q, want %q", got, tt.day)
+			}
+		})
+	}
+}
+
+func TestLocaleNumber(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		f        float64
+		decimals int
+		want     string
+	}{
+		{Locale{}, 1234567.891, 2, "1234567.89"},
+		{Locale{}, 1.25, 1, "1.2"},
+		{Locale{Decimal: ","}, 1.5, 1, "1,5"},
+		{Locale{Thousands: ","}, 1234567.891, 2, "1,234,567.89"},
+		{Locale{Decimal: ",", Thousands: "."}, 1234.5, 2, "1.234,50"},
+		{Locale{Thousands: " "}, 999, 0, "999"},
+		{Locale{Thousands: "'"}, 100000, 0, "100'000"},
+		{Locale{Thousands: ","}, -1234.5, 1, "-1,234.5"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.locale.Number(tt.f, tt.decimals); got != tt.want {
+			t.Errorf("%+v.Number(%v, %d) = %q, want %q", tt.locale, tt.f, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleTokens(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		n        int
+		decimals int
+		want     string
+	}{
+		{Locale{}, 500, 1, "500"},
+		{Locale{}, 32768, 0, "33K"},
+		{Locale{}, 1500, 1, "1.5K"},
+		{Locale{}, 1000000, 0, "1000K"},
+		{Locale{Suffix: "k"}, 200000, 0, "200k"},
+		{Locale{Suffix: "K/M"}, 1000000, 0, "1M"},
+		{Locale{Suffix: "K/M"}, 999999, 0, "1M"},
+		{Locale{Suffix: "K/M"}, 999999, 1, "1.0M"},
+		{Locale{Suffix: "K/M"}, 999949, 1, "999.9K"},
+		{Locale{Suffix: "K/M"}, 999950, 1, "1.0M"},
+		{Locale{Suffix: "K/M"}, 999499, 0, "999K"},
+		{Locale{Suffix: "K/M"}, 999500, 0, "1M"},
+		{Locale{}, 999999, 1, "1000.0K"}, // no millions suffix to move to
+		{Locale{Suffix: "k/m"}, 1500000, 1, "1.5m"},
+		{Locale{Decimal: ","}, 90000, 1, "90,0K"},
+		{Locale{Thousands: ","}, 2500000, 0, "2,500K"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.locale.Tokens(tt.n, tt.decimals); got != tt.want {
+			t.Errorf("%+v.Tokens(%d, %d) = %q, want %q", tt.locale, tt.n, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleCost(t *testing.T) {
+	if got := (Locale{}).Cost(1.234); got != "$1.23" {
+		t.Errorf("Cost = %q, want $1.23", got)
+	}
+	if got := (Locale{Decimal: ",", Thousands: "."}).Cost(1234.5); got != "$1.234,50" {
+		t.Errorf("Cost = %q, want $1.234,50", got)
+	}
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of the primary rate limit windows.
+const (
+	WindowFiveHour = "5h"
+	WindowSevenDay = "7d"
+)
+
+// RateWindow is one named rate limit window, e.g. "5h", "7d" or "7d-opus".
+type RateWindow struct {
+	Name    string
+	Percent float64
+	Reset   time.Time
+}
+
+// WindowName converts an API window key to its display name:
+// "five_hour" → "5h", "seven_day_opus" → "7d-opus".
+func WindowName(key string) string {
+	for prefix, name := range map[string]string{"five_hour": WindowFiveHour, "seven_day": WindowSevenDay} {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			if rest == "" {
+				return name
+			}
+			if rest[0] == '_' {
+				return name + "-" + strings.ReplaceAll(rest[1:], "_", "-")
+			}
+		}
+	}
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// Window returns the named window.
+func (d RateLimitData) Window(name string) (RateWindow, bool) {
+	for _, w := range d.Windows {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return RateWindow{}, false
+}
+
+// Weekly returns the 7d window that runs out first for a model family: the
+// shared 7d window, or the family's own (e.g. "7d-opus") when used more.
+func (d RateLimitData) Weekly(family string) RateWindow {
+	weekly := RateWindow{Name: WindowSevenDay, Percent: d.SevenDayPercent, Reset: d.SevenDayReset}
+	if family == "" {
+		return weekly
+	}
+	if own, ok := d.Window(WindowSevenDay + "-" + family); ok && own.Percent > weekly.Percent {
+		return own
+	}
+	return weekly
+}
+
+// UnmarshalJSON reads the 5h and 7d windows and extra_usage, and keeps every
+// other window-shaped entry (an object with "utilization") in Windows.
+func (r *RateLimitResponse) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = RateLimitResponse{}
+	for key, value := range raw {
+		if key == "extra_usage" {
+			var extra ExtraUsageResponse
+			if json.Unmarshal(value, &extra) == nil {
+				r.Extra = &extra
+			}
+			continue
+		}
+		var probe struct {
+			Utilization *float64 `json:"utilization"`
+			ResetsAt    *string  `json:"resets_at"`
+		}
+		if json.Unmarshal(value, &probe) != nil || probe.Utilization == nil {
+			continue
+		}
+		w := RateLimitWindow{Utilization: *probe.Utilization}
+		if probe.ResetsAt != nil {
+			w.ResetsAt = *probe.ResetsAt
+		}
+		switch key {
+		case "five_hour":
+			r.FiveHour = w
+		case "seven_day":
+			r.SevenDay = w
+		default:
+			if r.Windows == nil {
+				r.Windows = map[string]RateLimitWindow{}
+			}
+			r.Windows[key] = w
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes all windows under their API keys, and extra_usage.
+func (r RateLimitResponse) MarshalJSON() ([]byte, error) {
+	all := map[string]any{"five_hour": r.FiveHour, "seven_day": r.SevenDay}
+	for key, w := range r.Windows {
+		all[key] = w
+	}
+	if r.Extra != nil {
+		all["extra_usage"] = r.Extra
+	}
+	return json.Marshal(all)
+}
+
+// WindowKeys returns the keys of the extra windows, sorted.
+func (r RateLimitResponse) WindowKeys() []string {
+	keys := make([]string, 0, len(r.Windows))
+	for key := range r.Windows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
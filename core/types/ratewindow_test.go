@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWindowName(t *testing.T) {
+	for key, want := range map[string]string{
+		"five_hour":            "5h",
+		"seven_day":            "7d",
+		"seven_day_opus":       "7d-opus",
+		"seven_day_oauth_apps": "7d-oauth-apps",
+		"seven_dayz":           "seven-dayz",
+		"monthly":              "monthly",
+	} {
+		if got := WindowName(key); got != want {
+			t.Errorf("WindowName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRateLimitResponseJSON(t *testing.T) {
+	raw := `{
+		"five_hour": {"utilization": 46, "resets_at": "2026-03-05T15:00:00Z"},
+		"seven_day": {"utilization": 27, "resets_at": "2026-03-09T00:00:00Z"},
+		"seven_day_opus": {"utilization": 62, "resets_at": null},
+		"seven_day_sonnet": null,
+		"extra_usage": {"is_enabled": true, "monthly_limit": 5000, "used_credits": 1234, "utilization": 24.68}
+	}`
+	var resp RateLimitResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.FiveHour.Utilization != 46 || resp.SevenDay.ResetsAt != "2026-03-09T00:00:00Z" {
+		t.Errorf("primary windows = %+v %+v", resp.FiveHour, resp.SevenDay)
+	}
+	if len(resp.Windows) != 1 || resp.Windows["seven_day_opus"].Utilization != 62 {
+		t.Errorf("Windows = %+v, want only seven_day_opus", resp.Windows)
+	}
+	if resp.Extra == nil || !resp.Extra.IsEnabled || *resp.Extra.UsedCredits != 1234 || *resp.Extra.MonthlyLimit != 5000 {
+		t.Errorf("Extra = %+v, want enabled 1234/5000", resp.Extra)
+	}
+
+	// Round trip through the cache
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cached RateLimitResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatal(err)
+	}
+	if cached.SevenDay != resp.SevenDay || cached.Windows["seven_day_opus"] != resp.Windows["seven_day_opus"] ||
+		cached.Extra == nil || *cached.Extra.UsedCredits != 1234 {
+		t.Errorf("round trip = %+v, want %+v", cached, resp)
+	}
+}
+
+func TestWeekly(t *testing.T) {
+	reset := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	data := RateLimitData{
+		SevenDayPercent: 45,
+		SevenDayReset:   reset,
+		Windows: []RateWindow{
+			{Name: "5h", Percent: 10},
+			{Name: "7d", Percent: 45, Reset: reset},
+			{Name: "7d-opus", Percent: 62, Reset: reset},
+			{Name: "7d-sonnet", Percent: 5, Reset: reset},
+		},
+	}
+	tests := []struct {
+		family string
+		want   string
+	}{
+		{"opus", "7d-opus"}, // used more than the shared window
+		{"sonnet", "7d"},    // own window used less
+		{"haiku", "7d"},     // no own window
+		{"", "7d"},          // unknown model
+	}
+	for _, tt := range tests {
+		if got := data.Weekly(tt.family); got.Name != tt.want {
+			t.Errorf("Weekly(%q) = %q, want %q", tt.family, got.Name, tt.want)
+		}
+	}
+	if w := data.Weekly("opus"); w.Percent != 62 || !w.Reset.Equal(reset) {
+		t.Errorf("Weekly(opus) = %+v", w)
+	}
+}
@@ -1,19 +1,70 @@
 package types
 
-import "time"
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
 
 // Input represents the JSON structure read from stdin (provided by Claude Code).
 type Input struct {
-	ContextWindow ContextWindow    `json:"context_window"`
-	Model         Model            `json:"model"`
-	Cost          Cost             `json:"cost"`
-	RateLimits    *StdinRateLimits `json:"rate_limits,omitempty"`
+	HookEventName     string           `json:"hook_event_name"` // "Status"
+	SessionID         string           `json:"session_id"`
+	TranscriptPath    string           `json:"transcript_path"`
+	CWD               string           `json:"cwd"`
+	Workspace         Workspace        `json:"workspace"`
+	Version           string           `json:"version"` // Claude Code version, e.g. "2.1.80"
+	OutputStyle       OutputStyle      `json:"output_style"`
+	Exceeds200KTokens bool             `json:"exceeds_200k_tokens"`
+	ContextWindow     ContextWindow    `json:"context_window"`
+	Model             Model            `json:"model"`
+	Cost              Cost             `json:"cost"`
+	RateLimits        *StdinRateLimits `json:"rate_limits,omitempty"`
+}
+
+// Workspace holds the directories of the Claude Code session.
+type Workspace struct {
+	CurrentDir string `json:"current_dir"`
+	ProjectDir string `json:"project_dir"` // Directory Claude Code was started in
+}
+
+// OutputStyle identifies the active output style.
+type OutputStyle struct {
+	Name string `json:"name"`
+}
+
+// Dir returns the current working directory: workspace.current_dir, else cwd.
+func (i Input) Dir() string {
+	if i.Workspace.CurrentDir != "" {
+		return i.Workspace.CurrentDir
+	}
+	return i.CWD
+}
+
+// Project returns the project name and the current directory relative to the
+// project ("" at the project root or outside it): "statusline", "core/types".
+// The name is the last element of project_dir, else of the current directory.
+func (i Input) Project() (name, subdir string) {
+	dir := i.Dir()
+	root := i.Workspace.ProjectDir
+	if root == "" {
+		root = dir
+	}
+	if root == "" {
+		return "", ""
+	}
+	if rel, err := filepath.Rel(root, dir); err == nil && dir != "" && rel != "." && !strings.HasPrefix(rel, "..") {
+		subdir = filepath.ToSlash(rel)
+	}
+	return filepath.Base(root), subdir
 }
 
 // StdinRateLimits holds the rate limit data provided by Claude Code ≥2.1.80 via stdin.
 type StdinRateLimits struct {
 	FiveHour StdinRateWindow `json:"five_hour"`
 	SevenDay StdinRateWindow `json:"seven_day"`
+
+	ExtraUsage *ExtraUsageResponse `json:"extra_usage,omitempty"`
 }
 
 // StdinRateWindow holds a single rate limit window from stdin.
@@ -40,29 +91,51 @@ type CurrentUsage struct {
 
 // Model identifies the model being used.
 type Model struct {
+	ID          string `json:"id"`
 	DisplayName string `json:"display_name"`
-	ModelID     string `json:"model_id"`
+	ModelID     string `json:"model_id,omitempty"` // Name of id in older payloads
+}
+
+// Identifier returns the model ID: id, else the older model_id.
+func (m Model) Identifier() string {
+	if m.ID != "" {
+		return m.ID
+	}
+	return m.ModelID
 }
 
 // Cost holds session cost and duration data.
 type Cost struct {
-	TotalCostUSD      float64 `json:"total_cost_usd"`
-	TotalDurationMS   int     `json:"total_duration_ms"`
-	TotalLinesAdded   int     `json:"total_lines_added"`
-	TotalLinesRemoved int     `json:"total_lines_removed"`
+	TotalCostUSD       float64 `json:"total_cost_usd"`
+	TotalDurationMS    int     `json:"total_duration_ms"`
+	TotalAPIDurationMS int     `json:"total_api_duration_ms"` // Time spent waiting for API responses
+	TotalLinesAdded    int     `json:"total_lines_added"`
+	TotalLinesRemoved  int     `json:"total_lines_removed"`
 }
 
 // Config holds user configuration settings.
 type Config struct {
-	ContextWarningThreshold int           // 0 = disabled, 1-100 = show warning at this %
-	RateCacheTTL            time.Duration // How often to refresh rate limit data
-	WorkDaysPerWeek         int           // 1-7, used for 7-day pace scaling
-	CacheDir                string        // Directory for cache files
-	Version                 string        // Current binary version
-	CostNormalize           bool          // Normalize burn rate/pace by model cost weight
-	CostWeightHaiku         float64       // Cost weight for Haiku models (default 0.25)
-	CostWeightSonnet        float64       // Cost weight for Sonnet models (default 1.0)
-	CostWeightOpus          float64       // Cost weight for Opus models (default 5.0)
+	ContextWarningThreshold int               // 0 = disabled, 1-100 = show warning at this %
+	RateCacheTTL            time.Duration     // How often to refresh rate limit data
+	WorkDaysPerWeek         int               // 1-7, used for 7-day pace scaling
+	CacheDir                string            // Directory for cache files
+	Version                 string            // Current binary version
+	CostNormalize           bool              // Normalize burn rate/pace by model cost weight
+	CostWeightHaiku         float64           // Cost weight for Haiku models (default 0.25)
+	CostWeightSonnet        float64           // Cost weight for Sonnet models (default 1.0)
+	CostWeightOpus          float64           // Cost weight for Opus models (default 5.0)
+	Segments                [][]string        // Segment names per output line, in display order (nil = default for account mode)
+	MaxWidth                int               // Max visible columns per line (0 = unlimited)
+	Theme                   string            // Color theme: built-in name or user theme file name
+	NoColor                 bool              // Plain text output without escape codes
+	Renderer                string            // Output style: "ansi", "powerline" or "plain"
+	Formats                 map[string]string // Per-segment text/template overrides, keyed by segment name
+	ContextBar              BarStyle          // Context usage bar
+	FiveHourBar             BarStyle          // 5h usage/time split bar
+	SevenDayBar             BarStyle          // 7d usage/time split bar
+	Thresholds              Thresholds        // Warn/critical color levels per metric
+	Locale                  Locale            // Clock and number format
+	Diagnostics             bool              // Log stdin schema drift to the cache dir
 }
 
 // DefaultConfig returns configuration with sensible defaults.
@@ -77,13 +150,135 @@ func DefaultConfig() Config {
 		CostWeightHaiku:         0.25,
 		CostWeightSonnet:        1.0,
 		CostWeightOpus:          5.0,
+		Theme:                   "dark",
+		Renderer:                "ansi",
+		ContextBar:              DefaultBar(),
+		FiveHourBar:             DefaultBar(),
+		SevenDayBar:             DefaultBar(),
+		Thresholds:              DefaultThresholds(),
+	}
+}
+
+// Threshold holds the levels at which a metric turns warn and critical.
+type Threshold struct {
+	Warn float64
+	Crit float64
+}
+
+// Role maps value to ok/warn/critical by the threshold.
+func (t Threshold) Role(value float64) Role {
+	return LevelRole(value, t.Warn, t.Crit)
+}
+
+// Thresholds holds the color thresholds per metric.
+type Thresholds struct {
+	Context  Threshold // Context window usage (%)
+	FiveHour Threshold // 5h rate limit usage (%)
+	SevenDay Threshold // 7d rate limit usage (%)
+	Pace     Threshold // Cost-normalized pace (x)
+	Session  Threshold // Session cost ($)
+	Daily    Threshold // Daily cost ($)
+	PerHour  Threshold // Burn rate ($/h)
+	Extra    Threshold // Extra usage spend (% of the monthly limit)
+}
+
+// DefaultThresholds returns the original hard-coded thresholds.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Context:  Threshold{50, 80},
+		FiveHour: Threshold{50, 80},
+		SevenDay: Threshold{50, 80},
+		Pace:     Threshold{1.0, 1.5},
+		Session:  Threshold{0.50, 2.00},
+		Daily:    Threshold{5.00, 20.00},
+		PerHour:  Threshold{1.00, 5.00},
+		Extra:    Threshold{50, 80},
+	}
+}
+
+// BarStyle configures the look of one progress bar.
+type BarStyle struct {
+	Style  string    // Split bar time layer: one of BarStyles (ignored by single-layer bars)
+	Width  int       // Width in cells
+	Filled string    // Glyph of a used cell
+	Empty  string    // Glyph of an unused cell
+	Levels Threshold // Usage levels coloring the used cells (zero = 50/80)
+}
+
+// BarStyles lists the split bar styles, by how the elapsed-time layer is drawn:
+//
+//	thin           ▇ over a bar-time background where usage and time overlap, ▁ for time only
+//	bg             dim ░ on a bar-time background for time only
+//	lower-quarter  ▂ in bar-time for time only
+//	lower-half-dim ▄ in dim bar-time for time only
+//	dot            · in bar-time for time only
+var BarStyles = []string{"thin", "bg", "lower-quarter", "lower-half-dim", "dot"}
+
+// DefaultBar returns the default bar: 8 cells of █ and ░, thin split style.
+func DefaultBar() BarStyle {
+	return BarStyle{Style: "thin", Width: 8, Filled: "█", Empty: "░"}
+}
+
+// Role is a semantic color role, mapped to concrete colors by the renderer's theme.
+type Role string
+
+// Semantic color roles.
+const (
+	RoleOK        Role = "ok"         // Healthy values (low usage, low cost)
+	RoleWarn      Role = "warn"       // Values approaching a limit
+	RoleCritical  Role = "critical"   // Values at or over a limit, warnings
+	RoleDim       Role = "dim"        // Labels, units, separators
+	RoleAccent    Role = "accent"     // Notices (e.g. update available)
+	RoleBurn      Role = "burn"       // Burn rate figures
+	RoleResetTime Role = "reset-time" // Time until / time of a limit reset
+	RoleBarEmpty  Role = "bar-empty"  // Unfilled progress bar cells
+	RoleBarTime   Role = "bar-time"   // Elapsed-time layer of split bars
+)
+
+// Roles lists all semantic color roles.
+var Roles = []Role{RoleOK, RoleWarn, RoleCritical, RoleDim, RoleAccent, RoleBurn, RoleResetTime, RoleBarEmpty, RoleBarTime}
+
+// LevelRole maps a value to ok/warn/critical: below warn is ok, below crit is warn.
+func LevelRole(value, warn, crit float64) Role {
+	switch {
+	case value < warn:
+		return RoleOK
+	case value < crit:
+		return RoleWarn
+	default:
+		return RoleCritical
 	}
 }
 
+// Icon names a glyph drawn by the renderer's icon set.
+type Icon string
+
+// Icon names.
+const (
+	IconModel    Icon = "model"
+	IconContext  Icon = "context"
+	IconClock    Icon = "clock"
+	IconFlame    Icon = "flame"
+	IconCalendar Icon = "calendar"
+	IconLlama    Icon = "llama"
+	IconCost     Icon = "cost"
+	IconBranch   Icon = "branch"
+)
+
+// EmojiIcons is the default icon set. Icons without an emoji are not drawn.
+var EmojiIcons = map[Icon]string{
+	IconFlame:    "🔥",
+	IconCalendar: "📅",
+	IconLlama:    "🦙",
+	IconCost:     "💰",
+}
+
 // Credentials holds authentication credentials for the Anthropic API.
 type Credentials struct {
 	OAuthToken string
 	APIKey     string
+	AccountID  string // Account UUID of the OAuth login ("" = unknown, e.g. token from the environment)
+	Email      string // Email of the OAuth login ("" = unknown)
 }
 
 // HasOAuth returns true if OAuth credentials are available.
@@ -97,6 +292,7 @@ type ModelInfo struct {
 	DefaultContext int
 	IsLocal        bool
 	CostWeight     float64 // Cost weight for normalization (0 = unknown/use 1.0)
+	Family         string  // "opus", "sonnet", "haiku" or "" (unknown, local)
 }
 
 // ContextDisplay holds computed context window display data.
@@ -112,8 +308,19 @@ type ContextDisplay struct {
 
 // RateLimitResponse holds the API response for rate limits.
 type RateLimitResponse struct {
-	FiveHour RateLimitWindow `json:"five_hour"`
-	SevenDay RateLimitWindow `json:"seven_day"`
+	FiveHour RateLimitWindow            `json:"five_hour"`
+	SevenDay RateLimitWindow            `json:"seven_day"`
+	Windows  map[string]RateLimitWindow `json:"-"` // Other windows by API key, e.g. "seven_day_opus"
+	Extra    *ExtraUsageResponse        `json:"-"` // "extra_usage", nil when not reported
+}
+
+// ExtraUsageResponse holds the paid extra usage of a subscription, billed
+// once its limits are hit. Amounts are in cents.
+type ExtraUsageResponse struct {
+	IsEnabled    bool     `json:"is_enabled"`
+	MonthlyLimit *float64 `json:"monthly_limit"` // nil = no cap
+	UsedCredits  *float64 `json:"used_credits"`
+	Utilization  *float64 `json:"utilization"` // Percent of the monthly limit
 }
 
 // RateLimitWindow holds data for a single rate limit window.
@@ -128,19 +335,47 @@ type RateLimitData struct {
 	FiveHourReset   time.Time
 	SevenDayPercent float64
 	SevenDayReset   time.Time
+	Windows         []RateWindow // Every window: 5h, 7d, then the others by name
+	Extra           ExtraUsage   // Paid usage beyond the limits (zero = not reported)
 	FromCache       bool
+	Source          string    // RateSourceStdin, RateSourceAPI or RateSourceCache
+	FetchedAt       time.Time // When the data was received (cache file mtime for cached data)
 }
 
+// ExtraUsage is the paid usage of a subscription beyond its limits, in USD.
+type ExtraUsage struct {
+	Enabled bool
+	Used    float64 // Spent this month
+	Limit   float64 // Monthly limit (0 = none)
+}
+
+// Percent returns the share of the monthly limit spent, or 0 without a limit.
+func (e ExtraUsage) Percent() float64 {
+	if e.Limit <= 0 {
+		return 0
+	}
+	return e.Used / e.Limit * 100
+}
+
+// Rate limit data sources.
+const (
+	RateSourceStdin = "stdin" // rate_limits field of the Claude Code input
+	RateSourceAPI   = "api"   // fresh OAuth usage API response
+	RateSourceCache = "cache" // cached API response
+)
+
 // PaceInfo holds calculated pace information.
 type PaceInfo struct {
-	FiveHourPace     float64
-	FiveHourTimePct  int    // Percentage of 5h window elapsed (0-100)
-	SevenDayPace     float64
-	SevenDayTimePct  int    // Percentage of 7d window elapsed (0-100)
-	HittingLimit     bool
-	LimitETA         string // e.g., "~14:30" — when limit will be hit at current pace
-	ResetInfo        string // e.g., "→45m @14:30"
-	SevenDayResetFmt string
+	FiveHourPace    float64
+	FiveHourTimePct int // Percentage of 5h window elapsed (0-100)
+	SevenDayPace    float64
+	SevenDayTimePct int // Percentage of 7d window elapsed (0-100)
+	HittingLimit    bool
+	LimitETA        string    // e.g., "~14:30" — when limit will be hit at current pace
+	LimitAt         time.Time // When limit will be hit at current pace (zero when not hitting)
+	ResetIn         string    // e.g., "45m" — time until 5h reset, only when ≤60m
+	ResetAt         string    // e.g., "14:30" — local 5h reset time, only when ≤30m
+	SevenDayResetIn string    // e.g., "2d" or "<1d" — time until 7d reset, only when ≤3 days
 }
 
 // BurnInfo holds burn rate metrics.
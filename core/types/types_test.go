@@ -26,8 +26,8 @@ func TestInputUnmarshal(t *testing.T) {
 					"total_output_tokens": 3000
 				},
 				"model": {
-					"display_name": "Claude Sonnet 4",
-					"model_id": "claude-sonnet-4-20250514"
+					"id": "claude-sonnet-4-20250514",
+					"display_name": "Claude Sonnet 4"
 				},
 				"cost": {
 					"total_cost_usd": 0.50,
@@ -41,15 +41,15 @@ func TestInputUnmarshal(t *testing.T) {
 					i.ContextWindow.UsedPercentage != nil &&
 					*i.ContextWindow.UsedPercentage == 45.5 &&
 					i.ContextWindow.CurrentUsage.InputTokens == 5000 &&
-					i.Model.ModelID == "claude-sonnet-4-20250514" &&
+					i.Model.Identifier() == "claude-sonnet-4-20250514" &&
 					i.Cost.TotalCostUSD == 0.50
 			},
 		},
 		{
-			name: "minimal input",
+			name: "minimal input with the older model_id",
 			json: `{"model":{"model_id":"claude-sonnet-4-20250514"}}`,
 			check: func(i Input) bool {
-				return i.Model.ModelID == "claude-sonnet-4-20250514" &&
+				return i.Model.Identifier() == "claude-sonnet-4-20250514" &&
 					i.ContextWindow.UsedPercentage == nil &&
 					i.Cost.TotalCostUSD == 0
 			},
@@ -62,6 +62,30 @@ func TestInputUnmarshal(t *testing.T) {
 					i.ContextWindow.TotalInputTokens == 50000
 			},
 		},
+		{
+			name: "session fields",
+			json: `{
+				"session_id": "abc-123",
+				"transcript_path": "/home/u/.claude/projects/p/abc-123.jsonl",
+				"cwd": "/src/app/core",
+				"workspace": {"current_dir": "/src/app/core", "project_dir": "/src/app"},
+				"version": "2.1.80",
+				"output_style": {"name": "Explanatory"},
+				"exceeds_200k_tokens": true,
+				"cost": {"total_duration_ms": 600000, "total_api_duration_ms": 240000}
+			}`,
+			check: func(i Input) bool {
+				return i.SessionID == "abc-123" &&
+					i.TranscriptPath == "/home/u/.claude/projects/p/abc-123.jsonl" &&
+					i.CWD == "/src/app/core" &&
+					i.Workspace.CurrentDir == "/src/app/core" &&
+					i.Workspace.ProjectDir == "/src/app" &&
+					i.Version == "2.1.80" &&
+					i.OutputStyle.Name == "Explanatory" &&
+					i.Exceeds200KTokens &&
+					i.Cost.TotalAPIDurationMS == 240000
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,6 +101,31 @@ func TestInputUnmarshal(t *testing.T) {
 	}
 }
 
+func TestInputProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      Input
+		wantName   string
+		wantSubdir string
+	}{
+		{"empty", Input{}, "", ""},
+		{"cwd only", Input{CWD: "/src/app"}, "app", ""},
+		{"project root", Input{Workspace: Workspace{CurrentDir: "/src/app", ProjectDir: "/src/app"}}, "app", ""},
+		{"subdirectory", Input{Workspace: Workspace{CurrentDir: "/src/app/core/types", ProjectDir: "/src/app"}}, "app", "core/types"},
+		{"outside project", Input{Workspace: Workspace{CurrentDir: "/tmp", ProjectDir: "/src/app"}}, "app", ""},
+		{"current_dir over cwd", Input{CWD: "/src/app", Workspace: Workspace{CurrentDir: "/src/app/cmd", ProjectDir: "/src/app"}}, "app", "cmd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, subdir := tt.input.Project()
+			if name != tt.wantName || subdir != tt.wantSubdir {
+				t.Errorf("Project() = %q, %q, want %q, %q", name, subdir, tt.wantName, tt.wantSubdir)
+			}
+		})
+	}
+}
+
 func TestCredentialsHasOAuth(t *testing.T) {
 	tests := []struct {
 		creds    Credentials
@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/Benniphx/claude-statusline/core/ports"
-	"github.com/Benniphx/claude-statusline/adapter/render"
+	"github.com/Benniphx/claude-statusline/core/types"
 )
 
 const cacheFile = "claude_statusline_update.txt"
@@ -45,12 +45,13 @@ func Check(currentVersion string, cfg_cacheDir string, store ports.CacheStore, a
 }
 
 // Render produces the update notice string, or empty if no update.
+// Spacing around the notice is left to the layout.
 func Render(currentVersion, cacheDir string, store ports.CacheStore, api ports.APIClient, r ports.Renderer) string {
 	hasUpdate, _ := Check(currentVersion, cacheDir, store, api)
 	if !hasUpdate {
 		return ""
 	}
-	return " " + r.Color("[Update]", render.Yellow)
+	return r.Style("[Update]", types.RoleAccent)
 }
 
 // GreaterThan returns true if v1 is semantically greater than v2.
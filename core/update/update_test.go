@@ -68,6 +68,11 @@ func (m *mockCache) WriteFile(path string, data []byte) error {
 	return nil
 }
 
+func (m *mockCache) AppendFile(path string, data []byte) error {
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+
 func (m *mockCache) FileMTime(path string) (time.Time, error) {
 	return time.Now(), nil
 }
@@ -135,14 +140,14 @@ func TestCheckDevVersion(t *testing.T) {
 // mockRenderer for Render tests.
 type mockRenderer struct{}
 
-func (m *mockRenderer) Colorize(text string, percent int) string { return text }
-func (m *mockRenderer) Color(text, color string) string          { return text }
+func (m *mockRenderer) Style(text string, role types.Role) string         { return text }
 func (m *mockRenderer) Dim(text string) string                   { return text }
-func (m *mockRenderer) MakeBar(percent, width int) string                  { return "[bar]" }
-func (m *mockRenderer) MakeSplitBar(usagePct, timePct, width int) string   { return "[split]" }
+func (m *mockRenderer) MakeBar(percent int, bar types.BarStyle) string                  { return "[bar]" }
+func (m *mockRenderer) MakeSplitBar(usagePct, timePct int, bar types.BarStyle) string   { return "[split]" }
 func (m *mockRenderer) FormatTokens(n int) string                          { return "" }
 func (m *mockRenderer) FormatTokensF(n int) string               { return "" }
 func (m *mockRenderer) FormatCost(f float64) string              { return "" }
+func (m *mockRenderer) Icon(name types.Icon) string              { return types.EmojiIcons[name] }
 
 func TestRenderWithUpdate(t *testing.T) {
 	store := newMockCache()
@@ -157,8 +162,8 @@ func TestRenderWithUpdate(t *testing.T) {
 	if result == "" {
 		t.Error("Render should return update notice when update available")
 	}
-	if result != " [Update]" {
-		t.Errorf("Render = %q, want %q", result, " [Update]")
+	if result != "[Update]" {
+		t.Errorf("Render = %q, want %q", result, "[Update]")
 	}
 }
 
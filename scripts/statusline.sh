@@ -286,7 +286,7 @@ fi
 
 CONTEXT_SIZE=$(echo "$input" | jq -r '.context_window.context_window_size // 0')
 MODEL_RAW=$(echo "$input" | jq -r '.model.display_name // "Claude"')
-MODEL_ID=$(echo "$input" | jq -r '.model.model_id // ""' 2>/dev/null)
+MODEL_ID=$(echo "$input" | jq -r '.model.id // .model.model_id // ""' 2>/dev/null)
 COST=$(echo "$input" | jq -r '.cost.total_cost_usd // 0')
 DURATION_MS=$(echo "$input" | jq -r '.cost.total_duration_ms // 0')
 LINES_ADDED=$(echo "$input" | jq -r '.cost.total_lines_added // 0')